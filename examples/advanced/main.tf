@@ -134,7 +134,7 @@ resource "druid_kafka_supervisor" "advanced_example" {
     shutdown_timeout                        = "PT2M"
     
     index_spec {
-      bitmap = {
+      bitmap {
         type = "roaring"
       }
       dimension_compression = "lz4"
@@ -0,0 +1,331 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Defaults Druid fills in for sketch and expression aggregators
+const (
+	defaultHLLLgK                = 12
+	defaultHLLTgtHllType         = "HLL_4"
+	defaultThetaSize             = 16384
+	defaultQuantilesK            = 128
+	defaultAccumulatorIdentifier = "__acc"
+	defaultExpressionMaxSize     = 8192
+)
+
+// aggregatorOptions lists, per aggregator type, the attributes the type
+// accepts. The first group of each entry is required.
+var aggregatorOptions = map[string][2][]string{
+	"count":                  {nil, nil},
+	"HLLSketchBuild":         {{"field_name"}, {"lg_k", "tgt_hll_type", "round"}},
+	"HLLSketchMerge":         {{"field_name"}, {"lg_k", "tgt_hll_type", "round"}},
+	"thetaSketch":            {{"field_name"}, {"size", "is_input_theta_sketch"}},
+	"quantilesDoublesSketch": {{"field_name"}, {"k"}},
+	"cardinality":            {{"fields"}, {"by_row", "round"}},
+	"hyperUnique":            {{"field_name"}, {"is_input_hyper_unique", "round"}},
+	"filtered":               {{"filter", "aggregator"}, nil},
+	"expression": {
+		{"fold", "initial_value"},
+		{"fields", "combine", "compare", "finalize", "initial_combine_value", "accumulator_identifier", "max_size_bytes"},
+	},
+}
+
+// aggregatorAttributes are the attributes that only some aggregator types
+// accept. Types not listed in aggregatorOptions, such as the sum, min and max
+// aggregators, take a required field_name only.
+var aggregatorAttributes = []string{
+	"field_name", "fields", "lg_k", "tgt_hll_type", "round", "size", "is_input_theta_sketch", "k",
+	"by_row", "is_input_hyper_unique", "fold", "combine", "compare", "finalize", "initial_value",
+	"initial_combine_value", "accumulator_identifier", "max_size_bytes", "filter", "aggregator",
+}
+
+// aggregatorSchema returns the schema of an aggregator in metrics_spec. A
+// filtered aggregator wraps a further aggregator, which cannot be filtered
+// itself.
+func aggregatorSchema(filtered bool) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Metric name",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Aggregation type (count, longSum, doubleSum, HLLSketchBuild, thetaSketch, filtered, expression, etc.)",
+		},
+		"field_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Input field name for the metric",
+		},
+		"fields": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Input field names (cardinality, expression)",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"lg_k": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(4, 21),
+			Description:  "Log2 of the number of sketch buckets, defaults to 12 (HLLSketchBuild, HLLSketchMerge)",
+		},
+		"tgt_hll_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"HLL_4", "HLL_6", "HLL_8"}, false),
+			Description:  "Target HLL sketch type, defaults to HLL_4 (HLLSketchBuild, HLLSketchMerge)",
+		},
+		"round": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether estimates are rounded to whole numbers (HLLSketchBuild, HLLSketchMerge, cardinality, hyperUnique)",
+		},
+		"size": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validatePowerOfTwo(16, 1<<26),
+			Description:  "Maximum number of retained entries, a power of 2, defaults to 16384 (thetaSketch)",
+		},
+		"is_input_theta_sketch": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the input is already a theta sketch (thetaSketch)",
+		},
+		"k": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validatePowerOfTwo(2, 32768),
+			Description:  "Sketch accuracy parameter, a power of 2, defaults to 128 (quantilesDoublesSketch)",
+		},
+		"by_row": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the cardinality of distinct rows rather than values is computed (cardinality)",
+		},
+		"is_input_hyper_unique": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the input is already a hyperUnique (hyperUnique)",
+		},
+		"fold": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Expression folding each input into the accumulator (expression)",
+		},
+		"combine": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Expression combining two partial results (expression)",
+		},
+		"compare": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Expression comparing two results (expression)",
+		},
+		"finalize": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Expression computing the final result (expression)",
+		},
+		"initial_value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Initial accumulator value (expression)",
+		},
+		"initial_combine_value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Initial accumulator value when combining (expression)",
+		},
+		"accumulator_identifier": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the accumulator in the expressions, defaults to __acc (expression)",
+		},
+		"max_size_bytes": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Maximum size of array and string accumulators, defaults to 8192 (expression)",
+		},
+	}
+
+	if !filtered {
+		s["filter"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Filter selecting the rows that are aggregated (filtered)",
+			Elem:        filterSchema(maxFilterDepth),
+		}
+		s["aggregator"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Aggregator applied to the filtered rows (filtered)",
+			Elem:        aggregatorSchema(true),
+		}
+	}
+
+	return &schema.Resource{Schema: s}
+}
+
+// validatePowerOfTwo returns a SchemaValidateFunc checking that an integer is
+// a power of 2 within [min, max].
+func validatePowerOfTwo(min, max int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		v, ok := i.(int)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be integer", k)}
+		}
+		if v < min || v > max || v&(v-1) != 0 {
+			return nil, []error{fmt.Errorf("expected %s to be a power of 2 between %d and %d, got %d", k, min, max, v)}
+		}
+		return nil, nil
+	}
+}
+
+// buildAggregator converts a metrics_spec block into a Druid aggregator.
+// Only the attributes that are set are emitted.
+func buildAggregator(aggregator map[string]interface{}) map[string]interface{} {
+	agg := map[string]interface{}{
+		"name": aggregator["name"].(string),
+		"type": aggregator["type"].(string),
+	}
+
+	for key, druidKey := range map[string]string{
+		"field_name":             "fieldName",
+		"tgt_hll_type":           "tgtHllType",
+		"fold":                   "fold",
+		"combine":                "combine",
+		"compare":                "compare",
+		"finalize":               "finalize",
+		"initial_value":          "initialValue",
+		"initial_combine_value":  "initialCombineValue",
+		"accumulator_identifier": "accumulatorIdentifier",
+	} {
+		if v, _ := aggregator[key].(string); v != "" {
+			agg[druidKey] = v
+		}
+	}
+	for key, druidKey := range map[string]string{
+		"lg_k":           "lgK",
+		"size":           "size",
+		"k":              "k",
+		"max_size_bytes": "maxSizeBytes",
+	} {
+		if v, _ := aggregator[key].(int); v > 0 {
+			agg[druidKey] = v
+		}
+	}
+	for key, druidKey := range map[string]string{
+		"round":                 "round",
+		"is_input_theta_sketch": "isInputThetaSketch",
+		"by_row":                "byRow",
+		"is_input_hyper_unique": "isInputHyperUnique",
+	} {
+		if v, _ := aggregator[key].(bool); v {
+			agg[druidKey] = v
+		}
+	}
+	if fields, _ := aggregator["fields"].([]interface{}); len(fields) > 0 {
+		agg["fields"] = fields
+	}
+
+	if filter := firstBlock(aggregator["filter"]); filter != nil {
+		agg["filter"] = buildFilter(filter)
+	}
+	if inner := firstBlock(aggregator["aggregator"]); inner != nil {
+		agg["aggregator"] = buildAggregator(inner)
+	}
+
+	return agg
+}
+
+// validateAggregator checks that an aggregator sets the attributes its type
+// requires and no attributes of other types.
+func validateAggregator(aggregator map[string]interface{}, path string) error {
+	aggType, _ := aggregator["type"].(string)
+
+	options, known := aggregatorOptions[aggType]
+	if !known {
+		options = [2][]string{{"field_name"}, nil}
+	}
+
+	allowed := map[string]bool{}
+	for _, group := range options {
+		for _, key := range group {
+			allowed[key] = true
+		}
+	}
+	for _, key := range aggregatorAttributes {
+		if !allowed[key] && isSet(aggregator[key]) {
+			return fmt.Errorf("%s: %s is not supported by the %s aggregator", path, key, aggType)
+		}
+	}
+	for _, key := range options[0] {
+		if !isSet(aggregator[key]) {
+			return fmt.Errorf("%s: %s aggregator requires %s", path, aggType, key)
+		}
+	}
+
+	if aggType == "filtered" {
+		if err := validateFilter(firstBlock(aggregator["filter"]), path+".filter.0"); err != nil {
+			return err
+		}
+		inner := firstBlock(aggregator["aggregator"])
+		if inner["type"] == "filtered" {
+			return fmt.Errorf("%s.aggregator.0: filtered aggregators cannot be nested", path)
+		}
+		return validateAggregator(inner, path+".aggregator.0")
+	}
+
+	return nil
+}
+
+// flattenAggregator converts a Druid aggregator back into a metrics_spec
+// block. Sketch and expression defaults Druid fills in are dropped unless
+// they are set in prior, the aggregator currently in state.
+func flattenAggregator(agg map[string]interface{}, prior map[string]interface{}, filtered bool) map[string]interface{} {
+	result := map[string]interface{}{
+		"name":                   flattenString(agg["name"]),
+		"type":                   flattenString(agg["type"]),
+		"field_name":             flattenString(agg["fieldName"]),
+		"fields":                 flattenStringList(agg["fields"]),
+		"lg_k":                   flattenDefaultedInt(agg["lgK"], defaultHLLLgK, prior["lg_k"]),
+		"tgt_hll_type":           flattenDefaulted(agg["tgtHllType"], defaultHLLTgtHllType, prior["tgt_hll_type"]),
+		"round":                  flattenBool(agg["round"]),
+		"size":                   flattenDefaultedInt(agg["size"], defaultThetaSize, prior["size"]),
+		"is_input_theta_sketch":  flattenBool(agg["isInputThetaSketch"]),
+		"k":                      flattenDefaultedInt(agg["k"], defaultQuantilesK, prior["k"]),
+		"by_row":                 flattenBool(agg["byRow"]),
+		"is_input_hyper_unique":  flattenBool(agg["isInputHyperUnique"]),
+		"fold":                   flattenString(agg["fold"]),
+		"combine":                flattenString(agg["combine"]),
+		"compare":                flattenString(agg["compare"]),
+		"finalize":               flattenString(agg["finalize"]),
+		"initial_value":          flattenString(agg["initialValue"]),
+		"initial_combine_value":  flattenString(agg["initialCombineValue"]),
+		"accumulator_identifier": flattenDefaulted(agg["accumulatorIdentifier"], defaultAccumulatorIdentifier, prior["accumulator_identifier"]),
+		"max_size_bytes":         flattenDefaultedInt(agg["maxSizeBytes"], defaultExpressionMaxSize, prior["max_size_bytes"]),
+	}
+
+	if !filtered {
+		result["filter"] = []interface{}{}
+		if filter, ok := agg["filter"].(map[string]interface{}); ok {
+			result["filter"] = []interface{}{flattenFilter(filter, maxFilterDepth)}
+		}
+		result["aggregator"] = []interface{}{}
+		if inner, ok := agg["aggregator"].(map[string]interface{}); ok {
+			result["aggregator"] = []interface{}{flattenAggregator(inner, firstBlock(prior["aggregator"]), true)}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,322 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAggregator decodes a metrics_spec block through the schema, so that
+// unset attributes are filled in the way Terraform would.
+func testAggregator(t *testing.T, aggregator map[string]interface{}) map[string]interface{} {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"metrics_spec": []interface{}{aggregator},
+	})
+	return d.Get("metrics_spec.0").(map[string]interface{})
+}
+
+func TestBuildAggregator(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "count",
+			input: map[string]interface{}{
+				"name": "count",
+				"type": "count",
+			},
+			expected: map[string]interface{}{
+				"name": "count",
+				"type": "count",
+			},
+		},
+		{
+			name: "HLL sketch",
+			input: map[string]interface{}{
+				"name":         "unique_users",
+				"type":         "HLLSketchBuild",
+				"field_name":   "user_id",
+				"lg_k":         16,
+				"tgt_hll_type": "HLL_8",
+				"round":        true,
+			},
+			expected: map[string]interface{}{
+				"name":       "unique_users",
+				"type":       "HLLSketchBuild",
+				"fieldName":  "user_id",
+				"lgK":        16,
+				"tgtHllType": "HLL_8",
+				"round":      true,
+			},
+		},
+		{
+			name: "theta sketch",
+			input: map[string]interface{}{
+				"name":       "unique_sessions",
+				"type":       "thetaSketch",
+				"field_name": "session_id",
+				"size":       32768,
+			},
+			expected: map[string]interface{}{
+				"name":      "unique_sessions",
+				"type":      "thetaSketch",
+				"fieldName": "session_id",
+				"size":      32768,
+			},
+		},
+		{
+			name: "quantiles sketch",
+			input: map[string]interface{}{
+				"name":       "latency_sketch",
+				"type":       "quantilesDoublesSketch",
+				"field_name": "latency_ms",
+				"k":          256,
+			},
+			expected: map[string]interface{}{
+				"name":      "latency_sketch",
+				"type":      "quantilesDoublesSketch",
+				"fieldName": "latency_ms",
+				"k":         256,
+			},
+		},
+		{
+			name: "cardinality",
+			input: map[string]interface{}{
+				"name":   "distinct_pairs",
+				"type":   "cardinality",
+				"fields": []interface{}{"country", "city"},
+				"by_row": true,
+			},
+			expected: map[string]interface{}{
+				"name":   "distinct_pairs",
+				"type":   "cardinality",
+				"fields": []interface{}{"country", "city"},
+				"byRow":  true,
+			},
+		},
+		{
+			name: "filtered",
+			input: map[string]interface{}{
+				"name": "us_revenue",
+				"type": "filtered",
+				"filter": []interface{}{
+					map[string]interface{}{
+						"type":      "selector",
+						"dimension": "country",
+						"value":     "US",
+					},
+				},
+				"aggregator": []interface{}{
+					map[string]interface{}{
+						"name":       "us_revenue",
+						"type":       "doubleSum",
+						"field_name": "price",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"name": "us_revenue",
+				"type": "filtered",
+				"filter": map[string]interface{}{
+					"type":      "selector",
+					"dimension": "country",
+					"value":     "US",
+				},
+				"aggregator": map[string]interface{}{
+					"name":      "us_revenue",
+					"type":      "doubleSum",
+					"fieldName": "price",
+				},
+			},
+		},
+		{
+			name: "expression",
+			input: map[string]interface{}{
+				"name":          "max_price",
+				"type":          "expression",
+				"fields":        []interface{}{"price"},
+				"initial_value": "0.0",
+				"fold":          "greatest(__acc, price)",
+				"combine":       "greatest(__acc, max_price)",
+			},
+			expected: map[string]interface{}{
+				"name":         "max_price",
+				"type":         "expression",
+				"fields":       []interface{}{"price"},
+				"initialValue": "0.0",
+				"fold":         "greatest(__acc, price)",
+				"combine":      "greatest(__acc, max_price)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildAggregator(testAggregator(t, tt.input))
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestValidateAggregator(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:  "count",
+			input: map[string]interface{}{"name": "count", "type": "count"},
+		},
+		{
+			name:          "count with field name",
+			input:         map[string]interface{}{"name": "count", "type": "count", "field_name": "rows"},
+			expectedError: "metrics_spec.0: field_name is not supported by the count aggregator",
+		},
+		{
+			name:          "sum without field name",
+			input:         map[string]interface{}{"name": "added", "type": "longSum"},
+			expectedError: "metrics_spec.0: longSum aggregator requires field_name",
+		},
+		{
+			name:          "sketch option on another sketch",
+			input:         map[string]interface{}{"name": "users", "type": "thetaSketch", "field_name": "user_id", "lg_k": 12},
+			expectedError: "metrics_spec.0: lg_k is not supported by the thetaSketch aggregator",
+		},
+		{
+			name:          "cardinality without fields",
+			input:         map[string]interface{}{"name": "pairs", "type": "cardinality"},
+			expectedError: "metrics_spec.0: cardinality aggregator requires fields",
+		},
+		{
+			name:          "expression without fold",
+			input:         map[string]interface{}{"name": "max_price", "type": "expression", "initial_value": "0"},
+			expectedError: "metrics_spec.0: expression aggregator requires fold",
+		},
+		{
+			name: "filtered without aggregator",
+			input: map[string]interface{}{
+				"name": "us_count",
+				"type": "filtered",
+				"filter": []interface{}{
+					map[string]interface{}{"type": "selector", "dimension": "country", "value": "US"},
+				},
+			},
+			expectedError: "metrics_spec.0: filtered aggregator requires aggregator",
+		},
+		{
+			name: "filtered with invalid filter",
+			input: map[string]interface{}{
+				"name": "us_count",
+				"type": "filtered",
+				"filter": []interface{}{
+					map[string]interface{}{"type": "selector", "value": "US"},
+				},
+				"aggregator": []interface{}{
+					map[string]interface{}{"name": "us_count", "type": "count"},
+				},
+			},
+			expectedError: "metrics_spec.0.filter.0: selector filter requires dimension",
+		},
+		{
+			name: "filtered with invalid aggregator",
+			input: map[string]interface{}{
+				"name": "us_revenue",
+				"type": "filtered",
+				"filter": []interface{}{
+					map[string]interface{}{"type": "selector", "dimension": "country", "value": "US"},
+				},
+				"aggregator": []interface{}{
+					map[string]interface{}{"name": "us_revenue", "type": "doubleSum"},
+				},
+			},
+			expectedError: "metrics_spec.0.aggregator.0: doubleSum aggregator requires field_name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAggregator(testAggregator(t, tt.input), "metrics_spec.0")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidatePowerOfTwo(t *testing.T) {
+	validate := validatePowerOfTwo(16, 1024)
+
+	_, errs := validate(64, "size")
+	assert.Empty(t, errs)
+
+	_, errs = validate(100, "size")
+	assert.NotEmpty(t, errs)
+
+	_, errs = validate(8, "size")
+	assert.NotEmpty(t, errs)
+}
+
+func TestFlattenMetricsSpec(t *testing.T) {
+	// Aggregators as returned by Druid, with sketch defaults filled in
+	druidMetrics := []interface{}{
+		map[string]interface{}{
+			"name":       "unique_users",
+			"type":       "HLLSketchBuild",
+			"fieldName":  "user_id",
+			"lgK":        float64(12),
+			"tgtHllType": "HLL_4",
+			"round":      false,
+		},
+		map[string]interface{}{
+			"name": "us_sessions",
+			"type": "filtered",
+			"filter": map[string]interface{}{
+				"type":      "selector",
+				"dimension": "country",
+				"value":     "US",
+			},
+			"aggregator": map[string]interface{}{
+				"name":      "us_sessions",
+				"type":      "thetaSketch",
+				"fieldName": "session_id",
+				"size":      float64(16384),
+			},
+		},
+	}
+
+	result := flattenMetricsSpec(druidMetrics, nil)
+	require.Len(t, result, 2)
+
+	hll := result[0].(map[string]interface{})
+	assert.Equal(t, "HLLSketchBuild", hll["type"])
+	assert.Equal(t, 0, hll["lg_k"])
+	assert.Equal(t, "", hll["tgt_hll_type"])
+
+	filtered := result[1].(map[string]interface{})
+	assert.Equal(t, "filtered", filtered["type"])
+	assert.Equal(t, "country", filtered["filter"].([]interface{})[0].(map[string]interface{})["dimension"])
+	inner := filtered["aggregator"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "session_id", inner["field_name"])
+	assert.Equal(t, 0, inner["size"])
+
+	// Defaults that are set in the configuration are kept
+	prior := []interface{}{testAggregator(t, map[string]interface{}{
+		"name":       "unique_users",
+		"type":       "HLLSketchBuild",
+		"field_name": "user_id",
+		"lg_k":       12,
+	})}
+	result = flattenMetricsSpec(druidMetrics, prior)
+	assert.Equal(t, 12, result[0].(map[string]interface{})["lg_k"])
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("metrics_spec", result))
+}
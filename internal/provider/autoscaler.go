@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var lagAggregates = []string{"SUM", "MAX", "AVERAGE"}
+
+// autoScalerDefaults are the values Druid fills into a lag-based
+// autoScalerConfig for options that are not set.
+var autoScalerDefaults = map[string]interface{}{
+	"minTriggerScaleActionFrequencyMillis": 600000,
+	"lagCollectionIntervalMillis":          30000,
+	"lagCollectionRangeMillis":             600000,
+	"scaleOutThreshold":                    6000000,
+	"triggerScaleOutFractionThreshold":     0.3,
+	"scaleInThreshold":                     1000000,
+	"triggerScaleInFractionThreshold":      0.9,
+	"scaleActionStartDelayMillis":          300000,
+	"scaleActionPeriodMillis":              60000,
+	"scaleInStep":                          1,
+	"scaleOutStep":                         2,
+	"lagAggregate":                         "SUM",
+}
+
+// autoScalerIntOptions maps the optional integer attributes of
+// autoscaler_config to their Druid keys.
+var autoScalerIntOptions = map[string]string{
+	"task_count_start":                          "taskCountStart",
+	"min_trigger_scale_action_frequency_millis": "minTriggerScaleActionFrequencyMillis",
+	"lag_collection_interval_millis":            "lagCollectionIntervalMillis",
+	"lag_collection_range_millis":               "lagCollectionRangeMillis",
+	"scale_out_threshold":                       "scaleOutThreshold",
+	"scale_in_threshold":                        "scaleInThreshold",
+	"scale_action_start_delay_millis":           "scaleActionStartDelayMillis",
+	"scale_action_period_millis":                "scaleActionPeriodMillis",
+	"scale_in_step":                             "scaleInStep",
+	"scale_out_step":                            "scaleOutStep",
+}
+
+var autoScalerFloatOptions = map[string]string{
+	"trigger_scale_out_fraction_threshold": "triggerScaleOutFractionThreshold",
+	"trigger_scale_in_fraction_threshold":  "triggerScaleInFractionThreshold",
+}
+
+// autoScalerConfigSchema returns the schema of the lag-based task
+// autoscaler. Options left unset use Druid's defaults.
+func autoScalerConfigSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"enable_task_auto_scaler": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the supervisor scales its task count with consumer lag",
+			},
+			"task_count_max": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of reading tasks",
+			},
+			"task_count_min": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Minimum number of reading tasks",
+			},
+			"task_count_start": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of reading tasks to start with. Defaults to task_count_min",
+			},
+			"min_trigger_scale_action_frequency_millis": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Cooldown between scale actions in milliseconds. Druid defaults to 600000",
+			},
+			"lag_collection_interval_millis": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How often lag is sampled in milliseconds. Druid defaults to 30000",
+			},
+			"lag_collection_range_millis": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Window of lag samples considered in milliseconds. Druid defaults to 600000",
+			},
+			"scale_out_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Lag above which a sample counts toward scaling out. Druid defaults to 6000000",
+			},
+			"trigger_scale_out_fraction_threshold": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatBetween(0, 1),
+				Description:  "Fraction of samples above scale_out_threshold that triggers a scale out. Druid defaults to 0.3",
+			},
+			"scale_in_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Lag below which a sample counts toward scaling in. Druid defaults to 1000000",
+			},
+			"trigger_scale_in_fraction_threshold": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatBetween(0, 1),
+				Description:  "Fraction of samples below scale_in_threshold that triggers a scale in. Druid defaults to 0.9",
+			},
+			"scale_action_start_delay_millis": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Delay before the first scale action in milliseconds. Druid defaults to 300000",
+			},
+			"scale_action_period_millis": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How often scaling is evaluated in milliseconds. Druid defaults to 60000",
+			},
+			"scale_in_step": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Tasks removed per scale in. Druid defaults to 1",
+			},
+			"scale_out_step": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Tasks added per scale out. Druid defaults to 2",
+			},
+			"lag_aggregate": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringInSlice(lagAggregates, true),
+				DiffSuppressFunc: suppressCaseDifferences,
+				Description:      "How partition lags are combined (SUM, MAX or AVERAGE). Druid defaults to SUM",
+			},
+		},
+	}
+}
+
+// buildAutoScalerConfig converts an autoscaler_config block into a Druid
+// autoScalerConfig. Unset options are left to Druid.
+func buildAutoScalerConfig(config map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{
+		"enableTaskAutoScaler": config["enable_task_auto_scaler"].(bool),
+		"taskCountMax":         config["task_count_max"].(int),
+		"taskCountMin":         config["task_count_min"].(int),
+	}
+
+	for key, druidKey := range autoScalerIntOptions {
+		if v := config[key].(int); v > 0 {
+			c[druidKey] = v
+		}
+	}
+	for key, druidKey := range autoScalerFloatOptions {
+		if v := config[key].(float64); v > 0 {
+			c[druidKey] = v
+		}
+	}
+	if lagAggregate := config["lag_aggregate"].(string); lagAggregate != "" {
+		c["lagAggregate"] = lagAggregate
+	}
+
+	return c
+}
+
+// validateAutoScalerConfig checks that the task count bounds are ordered and,
+// when task_count is configured, that it lies within them.
+func validateAutoScalerConfig(config map[string]interface{}, taskCount int, taskCountSet bool, path string) error {
+	taskCountMin, _ := config["task_count_min"].(int)
+	taskCountMax, _ := config["task_count_max"].(int)
+
+	if taskCountMin > taskCountMax {
+		return fmt.Errorf("%s: task_count_min (%d) must not be greater than task_count_max (%d)", path, taskCountMin, taskCountMax)
+	}
+	if start, _ := config["task_count_start"].(int); start > 0 && (start < taskCountMin || start > taskCountMax) {
+		return fmt.Errorf("%s: task_count_start (%d) must be between task_count_min (%d) and task_count_max (%d)", path, start, taskCountMin, taskCountMax)
+	}
+	if enabled, _ := config["enable_task_auto_scaler"].(bool); enabled && taskCountSet && (taskCount < taskCountMin || taskCount > taskCountMax) {
+		return fmt.Errorf("task_count (%d) must be between %s.task_count_min (%d) and %s.task_count_max (%d)", taskCount, path, taskCountMin, path, taskCountMax)
+	}
+
+	return nil
+}
+
+// flattenAutoScalerConfig converts a Druid autoScalerConfig back into an
+// autoscaler_config block. Druid's defaults are dropped unless prior sets
+// them.
+func flattenAutoScalerConfig(config map[string]interface{}, prior map[string]interface{}) []interface{} {
+	c := map[string]interface{}{
+		"enable_task_auto_scaler": flattenBool(config["enableTaskAutoScaler"]),
+		"task_count_max":          flattenInt(config["taskCountMax"]),
+		"task_count_min":          flattenInt(config["taskCountMin"]),
+	}
+
+	for key, druidKey := range autoScalerIntOptions {
+		defaultValue, _ := autoScalerDefaults[druidKey].(int)
+		c[key] = flattenDefaultedInt(config[druidKey], defaultValue, prior[key])
+	}
+	for key, druidKey := range autoScalerFloatOptions {
+		c[key] = flattenDefaultedFloat(config[druidKey], autoScalerDefaults[druidKey].(float64), prior[key])
+	}
+	c["lag_aggregate"] = flattenDefaulted(config["lagAggregate"], autoScalerDefaults["lagAggregate"].(string), prior["lag_aggregate"])
+
+	return []interface{}{c}
+}
+
+// suppressAutoScaledTaskCount ignores task_count changes while the task
+// autoscaler is enabled, since the autoscaler rewrites taskCount as it scales.
+func suppressAutoScaledTaskCount(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" {
+		return false
+	}
+	enabled, _ := d.Get("autoscaler_config.0.enable_task_auto_scaler").(bool)
+	return enabled
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIOConfigAutoScaler(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "bounds only",
+			input: map[string]interface{}{
+				"enable_task_auto_scaler": true,
+				"task_count_min":          2,
+				"task_count_max":          8,
+			},
+			expected: map[string]interface{}{
+				"enableTaskAutoScaler": true,
+				"taskCountMin":         2,
+				"taskCountMax":         8,
+			},
+		},
+		{
+			name: "thresholds and cooldowns",
+			input: map[string]interface{}{
+				"enable_task_auto_scaler":                   true,
+				"task_count_min":                            1,
+				"task_count_max":                            10,
+				"task_count_start":                          2,
+				"lag_collection_interval_millis":            15000,
+				"scale_out_threshold":                       1000000,
+				"trigger_scale_out_fraction_threshold":      0.5,
+				"scale_in_threshold":                        100000,
+				"trigger_scale_in_fraction_threshold":       0.8,
+				"min_trigger_scale_action_frequency_millis": 300000,
+				"scale_out_step":                            3,
+				"lag_aggregate":                             "MAX",
+			},
+			expected: map[string]interface{}{
+				"enableTaskAutoScaler":                 true,
+				"taskCountMin":                         1,
+				"taskCountMax":                         10,
+				"taskCountStart":                       2,
+				"lagCollectionIntervalMillis":          15000,
+				"scaleOutThreshold":                    1000000,
+				"triggerScaleOutFractionThreshold":     0.5,
+				"scaleInThreshold":                     100000,
+				"triggerScaleInFractionThreshold":      0.8,
+				"minTriggerScaleActionFrequencyMillis": 300000,
+				"scaleOutStep":                         3,
+				"lagAggregate":                         "MAX",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"topic":             "test-topic",
+				"autoscaler_config": []interface{}{tt.input},
+			})
+			assert.Equal(t, tt.expected, buildIOConfig(d)["autoScalerConfig"])
+		})
+	}
+}
+
+func TestValidateAutoScalerConfig(t *testing.T) {
+	config := func(enabled bool, min, max, start int) map[string]interface{} {
+		return map[string]interface{}{
+			"enable_task_auto_scaler": enabled,
+			"task_count_min":          min,
+			"task_count_max":          max,
+			"task_count_start":        start,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		config       map[string]interface{}
+		taskCount    int
+		taskCountSet bool
+		wantErr      string
+	}{
+		{
+			name:         "task count within bounds",
+			config:       config(true, 2, 8, 0),
+			taskCount:    4,
+			taskCountSet: true,
+		},
+		{
+			name:      "task count left to the autoscaler",
+			config:    config(true, 2, 8, 0),
+			taskCount: 1,
+		},
+		{
+			name:         "disabled autoscaler ignores task count",
+			config:       config(false, 2, 8, 0),
+			taskCount:    1,
+			taskCountSet: true,
+		},
+		{
+			name:    "min above max",
+			config:  config(true, 8, 2, 0),
+			wantErr: "autoscaler_config.0: task_count_min (8) must not be greater than task_count_max (2)",
+		},
+		{
+			name:    "start outside bounds",
+			config:  config(true, 2, 8, 9),
+			wantErr: "task_count_start (9) must be between",
+		},
+		{
+			name:         "task count below min",
+			config:       config(true, 2, 8, 0),
+			taskCount:    1,
+			taskCountSet: true,
+			wantErr:      "task_count (1) must be between autoscaler_config.0.task_count_min (2) and autoscaler_config.0.task_count_max (8)",
+		},
+		{
+			name:         "task count above max",
+			config:       config(true, 2, 8, 0),
+			taskCount:    10,
+			taskCountSet: true,
+			wantErr:      "task_count (10) must be between",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAutoScalerConfig(tt.config, tt.taskCount, tt.taskCountSet, "autoscaler_config.0")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlattenAutoScalerConfig(t *testing.T) {
+	// An autoScalerConfig as returned by Druid, with defaults filled in
+	druidConfig := map[string]interface{}{
+		"enableTaskAutoScaler":                 true,
+		"taskCountMax":                         float64(8),
+		"taskCountMin":                         float64(2),
+		"taskCountStart":                       nil,
+		"minTriggerScaleActionFrequencyMillis": float64(600000),
+		"autoScalerStrategy":                   "lagBased",
+		"lagCollectionIntervalMillis":          float64(15000),
+		"lagCollectionRangeMillis":             float64(600000),
+		"scaleOutThreshold":                    float64(6000000),
+		"triggerScaleOutFractionThreshold":     0.3,
+		"scaleInThreshold":                     float64(1000000),
+		"triggerScaleInFractionThreshold":      0.9,
+		"scaleActionStartDelayMillis":          float64(300000),
+		"scaleActionPeriodMillis":              float64(60000),
+		"scaleInStep":                          float64(1),
+		"scaleOutStep":                         float64(2),
+		"lagAggregate":                         "SUM",
+	}
+
+	result := flattenAutoScalerConfig(druidConfig, map[string]interface{}{
+		"scale_out_step": 2,
+	})
+	require.Len(t, result, 1)
+	c := result[0].(map[string]interface{})
+	assert.Equal(t, true, c["enable_task_auto_scaler"])
+	assert.Equal(t, 8, c["task_count_max"])
+	assert.Equal(t, 2, c["task_count_min"])
+	assert.Equal(t, 0, c["task_count_start"])
+	assert.Equal(t, 15000, c["lag_collection_interval_millis"])
+	assert.Equal(t, 2, c["scale_out_step"])
+	assert.Equal(t, 0, c["scale_in_step"])
+	assert.Equal(t, 0, c["scale_out_threshold"])
+	assert.Equal(t, 0.0, c["trigger_scale_out_fraction_threshold"])
+	assert.Equal(t, "", c["lag_aggregate"])
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("autoscaler_config", result))
+}
+
+func TestSuppressAutoScaledTaskCount(t *testing.T) {
+	autoScaled := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"autoscaler_config": []interface{}{
+			map[string]interface{}{
+				"enable_task_auto_scaler": true,
+				"task_count_min":          1,
+				"task_count_max":          8,
+			},
+		},
+	})
+	assert.True(t, suppressAutoScaledTaskCount("task_count", "5", "1", autoScaled))
+	assert.False(t, suppressAutoScaledTaskCount("task_count", "", "1", autoScaled))
+
+	fixed := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"autoscaler_config": []interface{}{
+			map[string]interface{}{
+				"enable_task_auto_scaler": false,
+				"task_count_min":          1,
+				"task_count_max":          8,
+			},
+		},
+	})
+	assert.False(t, suppressAutoScaledTaskCount("task_count", "5", "1", fixed))
+	assert.False(t, suppressAutoScaledTaskCount("task_count", "5", "1", schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})))
+}
@@ -11,14 +11,54 @@ import (
 )
 
 type SupervisorStatus struct {
-	ID    string `json:"id"`
-	State string `json:"state"`
+	ID                 string            `json:"id"`
+	State              string            `json:"state"`
+	DetailedState      string            `json:"detailedState,omitempty"`
+	Healthy            bool              `json:"healthy"`
+	Partitions         int               `json:"partitions,omitempty"`
+	ActiveTasks        []SupervisorTask  `json:"activeTasks,omitempty"`
+	PublishingTasks    []SupervisorTask  `json:"publishingTasks,omitempty"`
+	AggregateLag       int64             `json:"aggregateLag,omitempty"`
+	MinimumLag         map[string]int64  `json:"minimumLag,omitempty"`
+	OffsetsLastUpdated string            `json:"offsetsLastUpdated,omitempty"`
+	RecentErrors       []SupervisorError `json:"recentErrors,omitempty"`
+}
+
+type SupervisorTask struct {
+	ID               string `json:"id"`
+	StartTime        string `json:"startTime"`
+	RemainingSeconds int64  `json:"remainingSeconds"`
+}
+
+type SupervisorError struct {
+	Timestamp       string `json:"timestamp"`
+	ExceptionClass  string `json:"exceptionClass"`
+	Message         string `json:"message"`
+	StreamException bool   `json:"streamException"`
+}
+
+// supervisorStatusResponse is the envelope returned by the status endpoint,
+// which reports the supervisor state inside its payload.
+type supervisorStatusResponse struct {
+	SupervisorStatus
+	Payload *SupervisorStatus `json:"payload"`
+}
+
+type SupervisorHistoryEntry struct {
+	Spec    map[string]interface{} `json:"spec"`
+	Version string                 `json:"version"`
 }
 
 type SupervisorResponse struct {
 	ID string `json:"id"`
 }
 
+// SupervisorSummary is an active supervisor as listed with its spec.
+type SupervisorSummary struct {
+	ID   string                 `json:"id"`
+	Spec map[string]interface{} `json:"spec"`
+}
+
 func (c *Client) CreateSupervisor(ctx context.Context, spec map[string]interface{}) (string, error) {
 	endpoint, err := url.JoinPath(c.Endpoint, "/druid/indexer/v1/supervisor")
 	if err != nil {
@@ -97,14 +137,201 @@ func (c *Client) GetSupervisor(ctx context.Context, supervisorID string) (*Super
 		return nil, fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var status SupervisorStatus
-	if err := json.Unmarshal(body, &status); err != nil {
+	var statusResp supervisorStatusResponse
+	if err := json.Unmarshal(body, &statusResp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal supervisor status: %w", err)
 	}
 
+	status := statusResp.SupervisorStatus
+	if statusResp.Payload != nil {
+		status = *statusResp.Payload
+		if status.ID == "" {
+			status.ID = statusResp.ID
+		}
+	}
+
 	return &status, nil
 }
 
+func (c *Client) GetSupervisorSpec(ctx context.Context, supervisorID string) (map[string]interface{}, error) {
+	endpoint, err := url.JoinPath(c.Endpoint, "/druid/indexer/v1/supervisor", supervisorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if c.Username != "" && c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(body, &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal supervisor spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+// ListSupervisors returns the active supervisors, running or suspended,
+// with their specs.
+func (c *Client) ListSupervisors(ctx context.Context) ([]SupervisorSummary, error) {
+	endpoint, err := url.JoinPath(c.Endpoint, "/druid/indexer/v1/supervisor")
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?full", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if c.Username != "" && c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var supervisors []SupervisorSummary
+	if err := json.Unmarshal(body, &supervisors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal supervisors: %w", err)
+	}
+
+	return supervisors, nil
+}
+
+// taskStatusResponse is the envelope returned by the task status endpoint.
+type taskStatusResponse struct {
+	Task   string `json:"task"`
+	Status *struct {
+		StatusCode string `json:"statusCode"`
+	} `json:"status"`
+}
+
+// GetTaskStatus returns the status code of an indexing task (RUNNING, SUCCESS
+// or FAILED), or an empty string if Druid does not know the task.
+func (c *Client) GetTaskStatus(ctx context.Context, taskID string) (string, error) {
+	endpoint, err := url.JoinPath(c.Endpoint, "/druid/indexer/v1/task", taskID, "status")
+	if err != nil {
+		return "", fmt.Errorf("failed to construct endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if c.Username != "" && c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var statusResp taskStatusResponse
+	if err := json.Unmarshal(body, &statusResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal task status: %w", err)
+	}
+
+	if statusResp.Status == nil {
+		return "", nil
+	}
+	return statusResp.Status.StatusCode, nil
+}
+
+func (c *Client) GetSupervisorHistory(ctx context.Context, supervisorID string) ([]SupervisorHistoryEntry, error) {
+	endpoint, err := url.JoinPath(c.Endpoint, "/druid/indexer/v1/supervisor", supervisorID, "history")
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if c.Username != "" && c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var history []SupervisorHistoryEntry
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal supervisor history: %w", err)
+	}
+
+	return history, nil
+}
+
 func (c *Client) DeleteSupervisor(ctx context.Context, supervisorID string) error {
 	endpoint, err := url.JoinPath(c.Endpoint, "/druid/indexer/v1/supervisor", supervisorID, "terminate")
 	if err != nil {
@@ -195,4 +422,74 @@ func (c *Client) ResumeSupervisor(ctx context.Context, supervisorID string) erro
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (c *Client) MarkDatasourceUnused(ctx context.Context, datasource string) error {
+	endpoint, err := url.JoinPath(c.Endpoint, "/druid/coordinator/v1/datasources", datasource)
+	if err != nil {
+		return fmt.Errorf("failed to construct endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if c.Username != "" && c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Datasource has no segments, nothing to mark unused
+		return nil
+	}
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// KillDatasource issues a kill task that permanently deletes all unused
+// segments of the datasource from deep storage.
+func (c *Client) KillDatasource(ctx context.Context, datasource string) error {
+	endpoint, err := url.JoinPath(c.Endpoint, "/druid/coordinator/v1/datasources", datasource, "intervals", "1000-01-01_3000-01-01")
+	if err != nil {
+		return fmt.Errorf("failed to construct endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if c.Username != "" && c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Datasource doesn't exist, nothing to kill
+		return nil
+	}
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Druid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
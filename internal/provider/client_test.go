@@ -150,6 +150,68 @@ func TestClient_GetSupervisor(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:           "status payload with recent errors",
+			supervisorID:   "test-supervisor",
+			responseStatus: http.StatusOK,
+			responseBody: `{"id": "test-supervisor", "generationTime": "2024-01-01T00:00:00.000Z", "payload": {
+				"dataSource": "test",
+				"state": "UNHEALTHY_SUPERVISOR",
+				"recentErrors": [{"timestamp": "2024-01-01T00:00:00.000Z", "exceptionClass": "org.apache.kafka.common.KafkaException", "message": "Failed to construct kafka consumer", "streamException": true}]
+			}}`,
+			expectedStatus: &SupervisorStatus{
+				ID:    "test-supervisor",
+				State: "UNHEALTHY_SUPERVISOR",
+				RecentErrors: []SupervisorError{
+					{
+						Timestamp:       "2024-01-01T00:00:00.000Z",
+						ExceptionClass:  "org.apache.kafka.common.KafkaException",
+						Message:         "Failed to construct kafka consumer",
+						StreamException: true,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:           "full status payload",
+			supervisorID:   "test-supervisor",
+			responseStatus: http.StatusOK,
+			responseBody: `{"id": "test-supervisor", "generationTime": "2024-01-01T00:00:00.000Z", "payload": {
+				"dataSource": "test",
+				"stream": "test-topic",
+				"partitions": 2,
+				"activeTasks": [{"id": "index_kafka_test_1", "startTime": "2024-01-01T00:00:00.000Z", "remainingSeconds": 1800}],
+				"publishingTasks": [],
+				"minimumLag": {"0": 10, "1": 5},
+				"aggregateLag": 15,
+				"offsetsLastUpdated": "2024-01-01T00:30:00.000Z",
+				"healthy": true,
+				"state": "RUNNING",
+				"detailedState": "RUNNING",
+				"recentErrors": []
+			}}`,
+			expectedStatus: &SupervisorStatus{
+				ID:            "test-supervisor",
+				State:         "RUNNING",
+				DetailedState: "RUNNING",
+				Healthy:       true,
+				Partitions:    2,
+				ActiveTasks: []SupervisorTask{
+					{
+						ID:               "index_kafka_test_1",
+						StartTime:        "2024-01-01T00:00:00.000Z",
+						RemainingSeconds: 1800,
+					},
+				},
+				PublishingTasks:    []SupervisorTask{},
+				AggregateLag:       15,
+				MinimumLag:         map[string]int64{"0": 10, "1": 5},
+				OffsetsLastUpdated: "2024-01-01T00:30:00.000Z",
+				RecentErrors:       []SupervisorError{},
+			},
+			expectError: false,
+		},
 		{
 			name:           "supervisor not found",
 			supervisorID:   "nonexistent-supervisor",
@@ -200,6 +262,293 @@ func TestClient_GetSupervisor(t *testing.T) {
 	}
 }
 
+func TestClient_GetSupervisorSpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		supervisorID   string
+		responseStatus int
+		responseBody   string
+		expectedSpec   map[string]interface{}
+		expectError    bool
+	}{
+		{
+			name:           "successful get",
+			supervisorID:   "test-supervisor",
+			responseStatus: http.StatusOK,
+			responseBody:   `{"type": "kafka", "spec": {"dataSchema": {"dataSource": "test"}}, "suspended": false}`,
+			expectedSpec: map[string]interface{}{
+				"type": "kafka",
+				"spec": map[string]interface{}{
+					"dataSchema": map[string]interface{}{
+						"dataSource": "test",
+					},
+				},
+				"suspended": false,
+			},
+			expectError: false,
+		},
+		{
+			name:           "supervisor not found",
+			supervisorID:   "nonexistent-supervisor",
+			responseStatus: http.StatusNotFound,
+			responseBody:   `{"error": "Not found"}`,
+			expectedSpec:   nil,
+			expectError:    false,
+		},
+		{
+			name:           "server error",
+			supervisorID:   "test-supervisor",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   `{"error": "Internal server error"}`,
+			expectedSpec:   nil,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				expectedPath := "/druid/indexer/v1/supervisor/" + tt.supervisorID
+				assert.Equal(t, expectedPath, r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+
+				w.WriteHeader(tt.responseStatus)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+				Username:   "",
+				Password:   "",
+			}
+
+			spec, err := client.GetSupervisorSpec(context.Background(), tt.supervisorID)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, spec)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSpec, spec)
+			}
+		})
+	}
+}
+
+func TestClient_ListSupervisors(t *testing.T) {
+	tests := []struct {
+		name                string
+		responseStatus      int
+		responseBody        string
+		expectedSupervisors []SupervisorSummary
+		expectError         bool
+	}{
+		{
+			name:           "successful list",
+			responseStatus: http.StatusOK,
+			responseBody: `[
+				{"id": "clicks", "state": "RUNNING", "spec": {"type": "kafka", "spec": {"dataSchema": {"dataSource": "events"}}}},
+				{"id": "views", "state": "SUSPENDED", "spec": {"type": "kafka"}}
+			]`,
+			expectedSupervisors: []SupervisorSummary{
+				{
+					ID: "clicks",
+					Spec: map[string]interface{}{
+						"type": "kafka",
+						"spec": map[string]interface{}{
+							"dataSchema": map[string]interface{}{"dataSource": "events"},
+						},
+					},
+				},
+				{
+					ID:   "views",
+					Spec: map[string]interface{}{"type": "kafka"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:                "server error",
+			responseStatus:      http.StatusInternalServerError,
+			responseBody:        `{"error": "Internal server error"}`,
+			expectedSupervisors: nil,
+			expectError:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/druid/indexer/v1/supervisor", r.URL.Path)
+				assert.True(t, r.URL.Query().Has("full"))
+				assert.Equal(t, http.MethodGet, r.Method)
+
+				w.WriteHeader(tt.responseStatus)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+			}
+
+			supervisors, err := client.ListSupervisors(context.Background())
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, supervisors)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSupervisors, supervisors)
+			}
+		})
+	}
+}
+
+func TestClient_GetTaskStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedStatus string
+		expectError    bool
+	}{
+		{
+			name:           "running task",
+			responseStatus: http.StatusOK,
+			responseBody:   `{"task": "index_kafka_test_1", "status": {"id": "index_kafka_test_1", "statusCode": "RUNNING", "runnerStatusCode": "RUNNING"}}`,
+			expectedStatus: "RUNNING",
+		},
+		{
+			name:           "finished task",
+			responseStatus: http.StatusOK,
+			responseBody:   `{"task": "index_kafka_test_1", "status": {"id": "index_kafka_test_1", "statusCode": "SUCCESS"}}`,
+			expectedStatus: "SUCCESS",
+		},
+		{
+			name:           "unknown task",
+			responseStatus: http.StatusNotFound,
+			responseBody:   `{"task": "index_kafka_test_1"}`,
+			expectedStatus: "",
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   `{"error": "Internal server error"}`,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/druid/indexer/v1/task/index_kafka_test_1/status", r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+
+				w.WriteHeader(tt.responseStatus)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+			}
+
+			status, err := client.GetTaskStatus(context.Background(), "index_kafka_test_1")
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestClient_GetSupervisorHistory(t *testing.T) {
+	tests := []struct {
+		name            string
+		supervisorID    string
+		responseStatus  int
+		responseBody    string
+		expectedHistory []SupervisorHistoryEntry
+		expectError     bool
+	}{
+		{
+			name:           "successful get",
+			supervisorID:   "test-supervisor",
+			responseStatus: http.StatusOK,
+			responseBody: `[
+				{"spec": {"type": "kafka", "suspended": false}, "version": "2024-01-02T00:00:00.000Z"},
+				{"spec": {"type": "kafka", "suspended": true}, "version": "2024-01-01T00:00:00.000Z"}
+			]`,
+			expectedHistory: []SupervisorHistoryEntry{
+				{
+					Spec:    map[string]interface{}{"type": "kafka", "suspended": false},
+					Version: "2024-01-02T00:00:00.000Z",
+				},
+				{
+					Spec:    map[string]interface{}{"type": "kafka", "suspended": true},
+					Version: "2024-01-01T00:00:00.000Z",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:            "supervisor not found",
+			supervisorID:    "nonexistent-supervisor",
+			responseStatus:  http.StatusNotFound,
+			responseBody:    `{"error": "Not found"}`,
+			expectedHistory: nil,
+			expectError:     false,
+		},
+		{
+			name:            "server error",
+			supervisorID:    "test-supervisor",
+			responseStatus:  http.StatusInternalServerError,
+			responseBody:    `{"error": "Internal server error"}`,
+			expectedHistory: nil,
+			expectError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				expectedPath := "/druid/indexer/v1/supervisor/" + tt.supervisorID + "/history"
+				assert.Equal(t, expectedPath, r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+
+				w.WriteHeader(tt.responseStatus)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+				Username:   "",
+				Password:   "",
+			}
+
+			history, err := client.GetSupervisorHistory(context.Background(), tt.supervisorID)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, history)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedHistory, history)
+			}
+		})
+	}
+}
+
 func TestClient_DeleteSupervisor(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -301,4 +650,71 @@ func TestClient_ResumeSupervisor(t *testing.T) {
 
 	err := client.ResumeSupervisor(context.Background(), "test-supervisor")
 	assert.NoError(t, err)
-}
\ No newline at end of file
+}
+
+func TestClient_MarkDatasourceUnused(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseStatus int
+		expectError    bool
+	}{
+		{
+			name:           "successful mark unused",
+			responseStatus: http.StatusOK,
+			expectError:    false,
+		},
+		{
+			name:           "datasource not found - should not error",
+			responseStatus: http.StatusNotFound,
+			expectError:    false,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/druid/coordinator/v1/datasources/test-datasource", r.URL.Path)
+				assert.Equal(t, http.MethodDelete, r.Method)
+				
+				w.WriteHeader(tt.responseStatus)
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+			}
+
+			err := client.MarkDatasourceUnused(context.Background(), "test-datasource")
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_KillDatasource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/druid/coordinator/v1/datasources/test-datasource/intervals/1000-01-01_3000-01-01", r.URL.Path)
+		assert.Equal(t, http.MethodDelete, r.Method)
+		
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+	}
+
+	err := client.KillDatasource(context.Background(), "test-datasource")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKafkaSupervisorHistory() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the spec versions Druid has recorded for a Kafka ingestion supervisor",
+
+		ReadContext: dataSourceKafkaSupervisorHistoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"supervisor_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The supervisor ID to list the spec history for",
+			},
+
+			"versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Spec versions, most recent first",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Version identifier, the time the spec was submitted",
+						},
+						"spec_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The supervisor spec submitted in this version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKafkaSupervisorHistoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	supervisorID := d.Get("supervisor_id").(string)
+
+	history, err := client.GetSupervisorHistory(ctx, supervisorID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if history == nil {
+		return diag.Errorf("supervisor %s not found", supervisorID)
+	}
+
+	// Versions are submission timestamps, which sort chronologically
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Version > history[j].Version
+	})
+
+	versions := []interface{}{}
+	for _, entry := range history {
+		specJSON, err := json.Marshal(entry.Spec)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to marshal spec version %s: %w", entry.Version, err))
+		}
+
+		versions = append(versions, map[string]interface{}{
+			"version":   entry.Version,
+			"spec_json": string(specJSON),
+		})
+	}
+
+	if err := d.Set("versions", versions); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(supervisorID)
+
+	return nil
+}
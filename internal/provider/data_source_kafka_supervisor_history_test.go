@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceKafkaSupervisorHistoryRead(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.AddSupervisorHistory("test-supervisor", SupervisorHistoryEntry{
+		Spec:    map[string]interface{}{"type": "kafka", "suspended": true},
+		Version: "2024-01-01T00:00:00.000Z",
+	})
+	mockServer.AddSupervisorHistory("test-supervisor", SupervisorHistoryEntry{
+		Spec:    map[string]interface{}{"type": "kafka", "suspended": false},
+		Version: "2024-01-02T00:00:00.000Z",
+	})
+
+	// A version recorded out of order is listed in version order
+	mockServer.AddSupervisorHistory("test-supervisor", SupervisorHistoryEntry{
+		Spec:    map[string]interface{}{"type": "kafka", "taskCount": float64(1)},
+		Version: "2023-12-31T00:00:00.000Z",
+	})
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := dataSourceKafkaSupervisorHistory().TestResourceData()
+	d.Set("supervisor_id", "test-supervisor")
+
+	diags := dataSourceKafkaSupervisorHistoryRead(context.Background(), d, client)
+	require.False(t, diags.HasError())
+
+	assert.Equal(t, "test-supervisor", d.Id())
+	assert.Equal(t, 3, d.Get("versions.#"))
+	assert.Equal(t, "2024-01-02T00:00:00.000Z", d.Get("versions.0.version"))
+	assert.JSONEq(t, `{"type": "kafka", "suspended": false}`, d.Get("versions.0.spec_json").(string))
+	assert.Equal(t, "2024-01-01T00:00:00.000Z", d.Get("versions.1.version"))
+	assert.Equal(t, "2023-12-31T00:00:00.000Z", d.Get("versions.2.version"))
+
+	// Unknown supervisors are an error
+	d = dataSourceKafkaSupervisorHistory().TestResourceData()
+	d.Set("supervisor_id", "missing-supervisor")
+
+	diags = dataSourceKafkaSupervisorHistoryRead(context.Background(), d, client)
+	assert.True(t, diags.HasError())
+}
@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// maxFilterDepth bounds how deeply and/or/not filters can be nested, since
+// Terraform schemas cannot be recursive.
+const maxFilterDepth = 4
+
+var filterTypes = []string{"selector", "in", "bound", "regex", "and", "or", "not", "expression"}
+
+var boundOrderings = []string{"lexicographic", "alphanumeric", "numeric", "strlen", "version"}
+
+// filterSchema returns the schema of a Druid filter. Logical filters nest
+// further filters up to depth levels.
+func filterSchema(depth int) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(filterTypes, false),
+			Description:  "Filter type (selector, in, bound, regex, and, or, not, expression)",
+		},
+		"dimension": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Dimension to filter on (selector, in, bound, regex)",
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Value to match (selector)",
+		},
+		"values": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Values to match (in)",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"lower": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Lower bound (bound)",
+		},
+		"upper": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Upper bound (bound)",
+		},
+		"lower_strict": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the lower bound is exclusive (bound)",
+		},
+		"upper_strict": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the upper bound is exclusive (bound)",
+		},
+		"ordering": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "lexicographic",
+			ValidateFunc: validation.StringInSlice(boundOrderings, false),
+			Description:  "Sort order used to compare values to the bounds (bound)",
+		},
+		"pattern": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Regular expression to match (regex)",
+		},
+		"expression": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Druid expression that must evaluate to true (expression)",
+		},
+	}
+
+	if depth > 1 {
+		s["fields"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Filters to combine (and, or)",
+			Elem:        filterSchema(depth - 1),
+		}
+		s["field"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Filter to negate (not)",
+			Elem:        filterSchema(depth - 1),
+		}
+	}
+
+	return &schema.Resource{Schema: s}
+}
+
+// buildFilter converts a filter block into a Druid filter. Only the keys
+// used by the filter's type are emitted.
+func buildFilter(filter map[string]interface{}) map[string]interface{} {
+	filterType := filter["type"].(string)
+	f := map[string]interface{}{
+		"type": filterType,
+	}
+
+	switch filterType {
+	case "selector":
+		f["dimension"] = filter["dimension"].(string)
+		f["value"] = filter["value"].(string)
+	case "in":
+		f["dimension"] = filter["dimension"].(string)
+		f["values"] = filter["values"].(*schema.Set).List()
+	case "bound":
+		f["dimension"] = filter["dimension"].(string)
+		if lower := filter["lower"].(string); lower != "" {
+			f["lower"] = lower
+			f["lowerStrict"] = filter["lower_strict"].(bool)
+		}
+		if upper := filter["upper"].(string); upper != "" {
+			f["upper"] = upper
+			f["upperStrict"] = filter["upper_strict"].(bool)
+		}
+		f["ordering"] = filter["ordering"].(string)
+	case "regex":
+		f["dimension"] = filter["dimension"].(string)
+		f["pattern"] = filter["pattern"].(string)
+	case "and", "or":
+		var fields []interface{}
+		if nested, ok := filter["fields"].([]interface{}); ok {
+			for _, field := range nested {
+				fields = append(fields, buildFilter(field.(map[string]interface{})))
+			}
+		}
+		f["fields"] = fields
+	case "not":
+		if nested, ok := filter["field"].([]interface{}); ok && len(nested) > 0 {
+			f["field"] = buildFilter(nested[0].(map[string]interface{}))
+		}
+	case "expression":
+		f["expression"] = filter["expression"].(string)
+	}
+
+	return f
+}
+
+// validateFilter checks that a filter block sets the attributes its type
+// requires. path prefixes the error messages.
+func validateFilter(filter map[string]interface{}, path string) error {
+	filterType, _ := filter["type"].(string)
+
+	require := func(keys ...string) error {
+		for _, key := range keys {
+			if v, _ := filter[key].(string); v == "" {
+				return fmt.Errorf("%s: %s filter requires %s", path, filterType, key)
+			}
+		}
+		return nil
+	}
+
+	switch filterType {
+	case "selector":
+		return require("dimension")
+	case "in":
+		if err := require("dimension"); err != nil {
+			return err
+		}
+		if values, ok := filter["values"].(*schema.Set); !ok || values.Len() == 0 {
+			return fmt.Errorf("%s: in filter requires values", path)
+		}
+	case "bound":
+		if err := require("dimension"); err != nil {
+			return err
+		}
+		lower, _ := filter["lower"].(string)
+		upper, _ := filter["upper"].(string)
+		if lower == "" && upper == "" {
+			return fmt.Errorf("%s: bound filter requires lower or upper", path)
+		}
+	case "regex":
+		return require("dimension", "pattern")
+	case "and", "or":
+		fields, _ := filter["fields"].([]interface{})
+		if len(fields) == 0 {
+			return fmt.Errorf("%s: %s filter requires fields", path, filterType)
+		}
+		for i, field := range fields {
+			if err := validateFilter(field.(map[string]interface{}), fmt.Sprintf("%s.fields.%d", path, i)); err != nil {
+				return err
+			}
+		}
+	case "not":
+		field, _ := filter["field"].([]interface{})
+		if len(field) == 0 {
+			return fmt.Errorf("%s: not filter requires field", path)
+		}
+		return validateFilter(field[0].(map[string]interface{}), path+".field.0")
+	case "expression":
+		return require("expression")
+	}
+
+	return nil
+}
+
+// flattenFilter converts a Druid filter back into a filter block. Filters
+// nested deeper than the schema allows are dropped.
+func flattenFilter(filter map[string]interface{}, depth int) map[string]interface{} {
+	f := map[string]interface{}{
+		"type":         flattenString(filter["type"]),
+		"dimension":    flattenString(filter["dimension"]),
+		"value":        flattenString(filter["value"]),
+		"values":       flattenStringList(filter["values"]),
+		"lower":        flattenString(filter["lower"]),
+		"upper":        flattenString(filter["upper"]),
+		"lower_strict": flattenBool(filter["lowerStrict"]),
+		"upper_strict": flattenBool(filter["upperStrict"]),
+		"ordering":     "lexicographic",
+		"pattern":      flattenString(filter["pattern"]),
+		"expression":   flattenString(filter["expression"]),
+	}
+
+	// Druid serializes the ordering as a comparator object.
+	switch ordering := filter["ordering"].(type) {
+	case string:
+		f["ordering"] = ordering
+	case map[string]interface{}:
+		if t := flattenString(ordering["type"]); t != "" {
+			f["ordering"] = t
+		}
+	}
+
+	if depth > 1 {
+		fields := []interface{}{}
+		if nested, ok := filter["fields"].([]interface{}); ok {
+			for _, field := range nested {
+				if m, ok := field.(map[string]interface{}); ok {
+					fields = append(fields, flattenFilter(m, depth-1))
+				}
+			}
+		}
+		f["fields"] = fields
+
+		field := []interface{}{}
+		if m, ok := filter["field"].(map[string]interface{}); ok {
+			field = append(field, flattenFilter(m, depth-1))
+		}
+		f["field"] = field
+	}
+
+	return f
+}
@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFilter decodes a filter block through the schema, so that defaults
+// and sets are filled in the way Terraform would.
+func testFilter(t *testing.T, filter map[string]interface{}) map[string]interface{} {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"transform_spec": []interface{}{
+			map[string]interface{}{
+				"filter": []interface{}{filter},
+			},
+		},
+	})
+	return d.Get("transform_spec.0.filter.0").(map[string]interface{})
+}
+
+func TestBuildFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "selector filter",
+			input: map[string]interface{}{
+				"type":      "selector",
+				"dimension": "country",
+				"value":     "US",
+			},
+			expected: map[string]interface{}{
+				"type":      "selector",
+				"dimension": "country",
+				"value":     "US",
+			},
+		},
+		{
+			name: "in filter",
+			input: map[string]interface{}{
+				"type":      "in",
+				"dimension": "country",
+				"values":    []interface{}{"US"},
+			},
+			expected: map[string]interface{}{
+				"type":      "in",
+				"dimension": "country",
+				"values":    []interface{}{"US"},
+			},
+		},
+		{
+			name: "bound filter",
+			input: map[string]interface{}{
+				"type":         "bound",
+				"dimension":    "age",
+				"lower":        "18",
+				"upper":        "65",
+				"upper_strict": true,
+				"ordering":     "numeric",
+			},
+			expected: map[string]interface{}{
+				"type":        "bound",
+				"dimension":   "age",
+				"lower":       "18",
+				"lowerStrict": false,
+				"upper":       "65",
+				"upperStrict": true,
+				"ordering":    "numeric",
+			},
+		},
+		{
+			name: "regex filter",
+			input: map[string]interface{}{
+				"type":      "regex",
+				"dimension": "path",
+				"pattern":   "^/api/",
+			},
+			expected: map[string]interface{}{
+				"type":      "regex",
+				"dimension": "path",
+				"pattern":   "^/api/",
+			},
+		},
+		{
+			name: "expression filter",
+			input: map[string]interface{}{
+				"type":       "expression",
+				"expression": "price > 0",
+			},
+			expected: map[string]interface{}{
+				"type":       "expression",
+				"expression": "price > 0",
+			},
+		},
+		{
+			name: "nested logical filters",
+			input: map[string]interface{}{
+				"type": "or",
+				"fields": []interface{}{
+					map[string]interface{}{
+						"type":       "expression",
+						"expression": "is_bot == 0",
+					},
+					map[string]interface{}{
+						"type": "not",
+						"field": []interface{}{
+							map[string]interface{}{
+								"type":      "selector",
+								"dimension": "status",
+								"value":     "deleted",
+							},
+						},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "or",
+				"fields": []interface{}{
+					map[string]interface{}{
+						"type":       "expression",
+						"expression": "is_bot == 0",
+					},
+					map[string]interface{}{
+						"type": "not",
+						"field": map[string]interface{}{
+							"type":      "selector",
+							"dimension": "status",
+							"value":     "deleted",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildFilter(testFilter(t, tt.input))
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "valid selector",
+			input: map[string]interface{}{
+				"type":      "selector",
+				"dimension": "country",
+				"value":     "US",
+			},
+		},
+		{
+			name: "selector without dimension",
+			input: map[string]interface{}{
+				"type":  "selector",
+				"value": "US",
+			},
+			expectedError: "filter.0: selector filter requires dimension",
+		},
+		{
+			name: "in without values",
+			input: map[string]interface{}{
+				"type":      "in",
+				"dimension": "country",
+			},
+			expectedError: "filter.0: in filter requires values",
+		},
+		{
+			name: "bound without bounds",
+			input: map[string]interface{}{
+				"type":      "bound",
+				"dimension": "age",
+			},
+			expectedError: "filter.0: bound filter requires lower or upper",
+		},
+		{
+			name: "regex without pattern",
+			input: map[string]interface{}{
+				"type":      "regex",
+				"dimension": "path",
+			},
+			expectedError: "filter.0: regex filter requires pattern",
+		},
+		{
+			name: "and without fields",
+			input: map[string]interface{}{
+				"type": "and",
+			},
+			expectedError: "filter.0: and filter requires fields",
+		},
+		{
+			name: "invalid nested filter",
+			input: map[string]interface{}{
+				"type": "and",
+				"fields": []interface{}{
+					map[string]interface{}{
+						"type":       "expression",
+						"expression": "price > 0",
+					},
+					map[string]interface{}{
+						"type": "not",
+						"field": []interface{}{
+							map[string]interface{}{
+								"type": "expression",
+							},
+						},
+					},
+				},
+			},
+			expectedError: "filter.0.fields.1.field.0: expression filter requires expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFilter(testFilter(t, tt.input), "filter.0")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestFlattenFilter(t *testing.T) {
+	// A filter as returned by Druid, with the comparator serialized as an object
+	druidFilter := map[string]interface{}{
+		"type": "not",
+		"field": map[string]interface{}{
+			"type":         "bound",
+			"dimension":    "age",
+			"lower":        "18",
+			"lowerStrict":  false,
+			"upper":        nil,
+			"upperStrict":  false,
+			"ordering":     map[string]interface{}{"type": "numeric"},
+			"extractionFn": nil,
+		},
+	}
+
+	result := flattenFilter(druidFilter, maxFilterDepth)
+
+	assert.Equal(t, "not", result["type"])
+	field := result["field"].([]interface{})
+	require.Len(t, field, 1)
+	bound := field[0].(map[string]interface{})
+	assert.Equal(t, "bound", bound["type"])
+	assert.Equal(t, "age", bound["dimension"])
+	assert.Equal(t, "18", bound["lower"])
+	assert.Equal(t, "", bound["upper"])
+	assert.Equal(t, "numeric", bound["ordering"])
+
+	// Filters nested deeper than the schema allows are dropped
+	leaf := flattenFilter(druidFilter, 1)
+	assert.NotContains(t, leaf, "field")
+}
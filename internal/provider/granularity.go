@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	// Time zones are validated against an embedded database, since provider
+	// binaries may run on hosts without one.
+	_ "time/tzdata"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// granularities are the named granularities Druid accepts.
+var granularities = []string{
+	"ALL", "NONE", "SECOND", "MINUTE", "FIVE_MINUTE", "TEN_MINUTE", "FIFTEEN_MINUTE", "THIRTY_MINUTE",
+	"HOUR", "SIX_HOUR", "EIGHT_HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR",
+}
+
+const defaultPeriodTimeZone = "UTC"
+
+// standardGranularityPeriods maps the periods of Druid's named granularities
+// to their names. Druid reports a UTC period granularity without origin
+// whose period is one of these by its name.
+var standardGranularityPeriods = map[string]string{
+	"PT1S":  "SECOND",
+	"PT1M":  "MINUTE",
+	"PT5M":  "FIVE_MINUTE",
+	"PT10M": "TEN_MINUTE",
+	"PT15M": "FIFTEEN_MINUTE",
+	"PT30M": "THIRTY_MINUTE",
+	"PT1H":  "HOUR",
+	"PT6H":  "SIX_HOUR",
+	"PT8H":  "EIGHT_HOUR",
+	"P1D":   "DAY",
+	"P1W":   "WEEK",
+	"P1M":   "MONTH",
+	"P3M":   "QUARTER",
+	"P1Y":   "YEAR",
+}
+
+var timeZoneOffsetPattern = regexp.MustCompile(`^[+-]\d{2}:\d{2}$`)
+
+// periodGranularitySchema returns the schema of a period granularity, which
+// buckets time by an ISO 8601 period in a time zone.
+func periodGranularitySchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"period": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateISO8601Period,
+				Description:  "ISO 8601 period of the buckets, such as P1D or PT15M",
+			},
+			"time_zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTimeZone,
+				Description:  "Time zone the buckets are aligned to, such as America/Los_Angeles. Defaults to UTC",
+			},
+			"origin": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateISO8601DateTime,
+				Description:  "ISO 8601 timestamp buckets start from",
+			},
+		},
+	}
+}
+
+// namedGranularitySchema returns the schema of a granularity given by name.
+func namedGranularitySchema(defaultValue, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Default:          defaultValue,
+		ValidateFunc:     validation.StringInSlice(granularities, true),
+		DiffSuppressFunc: suppressCaseDifferences,
+		Description:      description,
+	}
+}
+
+// periodGranularityAttribute returns the schema of the period granularity
+// block used instead of the named granularity key.
+func periodGranularityAttribute(key string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"granularity_spec.0." + key},
+		Description:   fmt.Sprintf("Period granularity used instead of %s", key),
+		Elem:          periodGranularitySchema(),
+	}
+}
+
+// buildGranularity returns the named granularity, or the period granularity
+// when one is set.
+func buildGranularity(name string, periods interface{}) interface{} {
+	period := firstBlock(periods)
+	if period == nil {
+		return name
+	}
+
+	g := map[string]interface{}{
+		"type":   "period",
+		"period": period["period"].(string),
+	}
+	if timeZone := period["time_zone"].(string); timeZone != "" {
+		g["timeZone"] = timeZone
+	}
+	if origin := period["origin"].(string); origin != "" {
+		g["origin"] = origin
+	}
+	return g
+}
+
+// flattenGranularityValue reads a granularity back as a name or a period
+// granularity block. When Druid returns a period granularity or none at all,
+// the name keeps its prior value, falling back to defaultName.
+func flattenGranularityValue(v interface{}, priorName interface{}, priorPeriods interface{}, defaultName string) (string, []interface{}) {
+	name := defaultName
+	if prior := flattenString(priorName); prior != "" {
+		name = prior
+	}
+
+	g, ok := v.(map[string]interface{})
+	if !ok || flattenString(g["type"]) != "period" {
+		if v == nil {
+			return name, []interface{}{}
+		}
+		return flattenGranularity(v), []interface{}{}
+	}
+
+	prior := firstBlock(priorPeriods)
+
+	return name, []interface{}{
+		map[string]interface{}{
+			"period":    flattenString(g["period"]),
+			"time_zone": flattenDefaulted(g["timeZone"], defaultPeriodTimeZone, prior["time_zone"]),
+			"origin":    flattenString(g["origin"]),
+		},
+	}
+}
+
+// validateGranularitySpec checks the combinations of granularity_spec
+// attributes the schema cannot express.
+func validateGranularitySpec(granularitySpec map[string]interface{}, path string) error {
+	intervals, _ := granularitySpec["intervals"].([]interface{})
+
+	if granularitySpec["type"] == "arbitrary" {
+		if len(intervals) == 0 {
+			return fmt.Errorf("%s: arbitrary granularity spec requires intervals", path)
+		}
+		if isSet(granularitySpec["segment_granularity_period"]) {
+			return fmt.Errorf("%s: segment_granularity_period is not supported by the arbitrary granularity spec", path)
+		}
+	}
+
+	for _, key := range []string{"segment_granularity", "query_granularity"} {
+		if name := standardGranularityName(firstBlock(granularitySpec[key+"_period"])); name != "" {
+			return fmt.Errorf("%s.%s_period.0: Druid reports this period granularity as %s, set %s = %q instead", path, key, name, key, name)
+		}
+	}
+
+	return nil
+}
+
+// standardGranularityName returns the name Druid reports a period
+// granularity block by, or "" when it stays a period granularity.
+func standardGranularityName(period map[string]interface{}) string {
+	if period == nil {
+		return ""
+	}
+	if origin, _ := period["origin"].(string); origin != "" {
+		return ""
+	}
+	switch timeZone, _ := period["time_zone"].(string); timeZone {
+	case "", defaultPeriodTimeZone, "+00:00":
+	default:
+		return ""
+	}
+	periodValue, _ := period["period"].(string)
+	return standardGranularityPeriods[strings.ToUpper(periodValue)]
+}
+
+func validateTimeZone(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if timeZoneOffsetPattern.MatchString(v) {
+		return nil, nil
+	}
+	if _, err := time.LoadLocation(v); err != nil || v == "" || v == "Local" {
+		return nil, []error{fmt.Errorf("expected %s to be a time zone ID such as America/Los_Angeles or an offset such as +05:30, got %q", k, v)}
+	}
+	return nil, nil
+}
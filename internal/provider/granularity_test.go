@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDataSchemaGranularitySpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "period granularities",
+			input: map[string]interface{}{
+				"segment_granularity_period": []interface{}{
+					map[string]interface{}{
+						"period":    "P1D",
+						"time_zone": "America/Los_Angeles",
+					},
+				},
+				"query_granularity_period": []interface{}{
+					map[string]interface{}{
+						"period": "PT15M",
+						"origin": "2020-01-01T00:05:00Z",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "uniform",
+				"segmentGranularity": map[string]interface{}{
+					"type":     "period",
+					"period":   "P1D",
+					"timeZone": "America/Los_Angeles",
+				},
+				"queryGranularity": map[string]interface{}{
+					"type":   "period",
+					"period": "PT15M",
+					"origin": "2020-01-01T00:05:00Z",
+				},
+				"rollup": true,
+			},
+		},
+		{
+			name: "arbitrary with intervals",
+			input: map[string]interface{}{
+				"type":              "arbitrary",
+				"query_granularity": "HOUR",
+				"intervals":         []interface{}{"2020-01-01/2020-02-01", "2020-03-01/2020-04-01"},
+				"rollup":            false,
+			},
+			expected: map[string]interface{}{
+				"type":             "arbitrary",
+				"queryGranularity": "HOUR",
+				"intervals":        []interface{}{"2020-01-01/2020-02-01", "2020-03-01/2020-04-01"},
+				"rollup":           false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"datasource":       "test-datasource",
+				"granularity_spec": []interface{}{tt.input},
+			})
+			assert.Equal(t, tt.expected, buildDataSchema(d)["granularitySpec"])
+		})
+	}
+}
+
+func TestValidateGranularitySpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "uniform",
+			input: map[string]interface{}{
+				"segment_granularity": "DAY",
+			},
+		},
+		{
+			name: "arbitrary with intervals",
+			input: map[string]interface{}{
+				"type":      "arbitrary",
+				"intervals": []interface{}{"2020-01-01/2020-02-01"},
+			},
+		},
+		{
+			name: "arbitrary without intervals",
+			input: map[string]interface{}{
+				"type": "arbitrary",
+			},
+			wantErr: "granularity_spec.0: arbitrary granularity spec requires intervals",
+		},
+		{
+			name: "arbitrary with segment granularity period",
+			input: map[string]interface{}{
+				"type":      "arbitrary",
+				"intervals": []interface{}{"2020-01-01/2020-02-01"},
+				"segment_granularity_period": []interface{}{
+					map[string]interface{}{"period": "P1D"},
+				},
+			},
+			wantErr: "segment_granularity_period is not supported",
+		},
+		{
+			name: "period in another time zone",
+			input: map[string]interface{}{
+				"segment_granularity_period": []interface{}{
+					map[string]interface{}{"period": "P1D", "time_zone": "America/Los_Angeles"},
+				},
+			},
+		},
+		{
+			name: "period with origin",
+			input: map[string]interface{}{
+				"query_granularity_period": []interface{}{
+					map[string]interface{}{"period": "PT15M", "origin": "2024-01-01T00:05:00Z"},
+				},
+			},
+		},
+		{
+			name: "non-standard period in UTC",
+			input: map[string]interface{}{
+				"segment_granularity_period": []interface{}{
+					map[string]interface{}{"period": "PT2H"},
+				},
+			},
+		},
+		{
+			name: "standard period in UTC",
+			input: map[string]interface{}{
+				"segment_granularity_period": []interface{}{
+					map[string]interface{}{"period": "P1D"},
+				},
+			},
+			wantErr: `granularity_spec.0.segment_granularity_period.0: Druid reports this period granularity as DAY, set segment_granularity = "DAY" instead`,
+		},
+		{
+			name: "standard period with explicit UTC",
+			input: map[string]interface{}{
+				"query_granularity_period": []interface{}{
+					map[string]interface{}{"period": "PT15M", "time_zone": "UTC"},
+				},
+			},
+			wantErr: `set query_granularity = "FIFTEEN_MINUTE" instead`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"granularity_spec": []interface{}{tt.input},
+			})
+			err := validateGranularitySpec(firstBlock(d.Get("granularity_spec")), "granularity_spec.0")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGranularitySpecSchemaValidation(t *testing.T) {
+	config := func(granularitySpec map[string]interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"datasource": "test-datasource",
+			"timestamp_spec": []interface{}{
+				map[string]interface{}{"column": "__time"},
+			},
+			"topic":               "test-topic",
+			"input_format":        []interface{}{map[string]interface{}{"type": "json"}},
+			"consumer_properties": map[string]interface{}{"bootstrap.servers": "localhost:9092"},
+			"granularity_spec":    []interface{}{granularitySpec},
+		})
+	}
+
+	valid := []map[string]interface{}{
+		{"segment_granularity": "fifteen_minute", "query_granularity": "ALL"},
+		{
+			"query_granularity_period": []interface{}{
+				map[string]interface{}{"period": "PT5M", "time_zone": "+05:30"},
+			},
+		},
+	}
+	for _, granularitySpec := range valid {
+		assert.False(t, resourceKafkaSupervisor().Validate(config(granularitySpec)).HasError(), granularitySpec)
+	}
+
+	invalid := []map[string]interface{}{
+		{"segment_granularity": "FORTNIGHT"},
+		{"type": "period"},
+		{
+			"segment_granularity": "DAY",
+			"segment_granularity_period": []interface{}{
+				map[string]interface{}{"period": "P1D"},
+			},
+		},
+		{
+			"segment_granularity_period": []interface{}{
+				map[string]interface{}{"period": "P1D", "time_zone": "Mars/Olympus_Mons"},
+			},
+		},
+		{"intervals": []interface{}{"2020-01-01"}},
+	}
+	for _, granularitySpec := range invalid {
+		assert.True(t, resourceKafkaSupervisor().Validate(config(granularitySpec)).HasError(), granularitySpec)
+	}
+}
+
+func TestFlattenGranularitySpec(t *testing.T) {
+	t.Run("period granularities", func(t *testing.T) {
+		gs := map[string]interface{}{
+			"type": "uniform",
+			"segmentGranularity": map[string]interface{}{
+				"type":     "period",
+				"period":   "P1D",
+				"timeZone": "America/Los_Angeles",
+				"origin":   nil,
+			},
+			"queryGranularity": map[string]interface{}{
+				"type":     "period",
+				"period":   "PT15M",
+				"timeZone": "UTC",
+				"origin":   "2020-01-01T00:05:00.000Z",
+			},
+			"rollup": true,
+		}
+
+		result := flattenGranularitySpec(gs, map[string]interface{}{"query_granularity": "MINUTE"})
+		require.Len(t, result, 1)
+		flat := result[0].(map[string]interface{})
+		assert.Equal(t, "HOUR", flat["segment_granularity"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"period": "P1D", "time_zone": "America/Los_Angeles", "origin": ""},
+		}, flat["segment_granularity_period"])
+		assert.Equal(t, "MINUTE", flat["query_granularity"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"period": "PT15M", "time_zone": "", "origin": "2020-01-01T00:05:00.000Z"},
+		}, flat["query_granularity_period"])
+	})
+
+	t.Run("arbitrary", func(t *testing.T) {
+		gs := map[string]interface{}{
+			"type":             "arbitrary",
+			"queryGranularity": map[string]interface{}{"type": "none"},
+			"intervals":        []interface{}{"2020-01-01T00:00:00.000Z/2020-02-01T00:00:00.000Z"},
+			"rollup":           true,
+		}
+
+		flat := flattenGranularitySpec(gs, nil)[0].(map[string]interface{})
+		assert.Equal(t, "arbitrary", flat["type"])
+		assert.Equal(t, "HOUR", flat["segment_granularity"])
+		assert.Equal(t, "NONE", flat["query_granularity"])
+		assert.Equal(t, []interface{}{"2020-01-01T00:00:00.000Z/2020-02-01T00:00:00.000Z"}, flat["intervals"])
+	})
+}
+
+func TestValidateTimeZone(t *testing.T) {
+	for _, v := range []string{"UTC", "America/Los_Angeles", "Asia/Kolkata", "+05:30", "-08:00"} {
+		_, errs := validateTimeZone(v, "time_zone")
+		assert.Empty(t, errs, v)
+	}
+	for _, v := range []string{"", "Local", "PST8", "Mars/Olympus_Mons", "+5:30"} {
+		_, errs := validateTimeZone(v, "time_zone")
+		assert.Len(t, errs, 1, v)
+	}
+}
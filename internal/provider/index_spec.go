@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	defaultCompression     = "lz4"
+	defaultBitmapType      = "roaring"
+	defaultStringEncoding  = "utf8"
+	defaultFrontCodedSize  = 4
+	defaultLongEncoding    = "longs"
+	frontCodedEncodingType = "frontCoded"
+)
+
+// indexSpecAttributes are the tuning_config attributes holding index specs.
+var indexSpecAttributes = []string{"index_spec", "index_spec_for_intermediate_persists"}
+
+var (
+	dimensionCompressions = []string{"lz4", "lzf", "zstd", "uncompressed"}
+	metricCompressions    = []string{"lz4", "lzf", "zstd", "uncompressed", "none"}
+)
+
+// indexSpecSchema returns the schema of a segment index spec, shared by the
+// published and intermediate-persist index specs. Options left unset use
+// Druid's defaults.
+func indexSpecSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"bitmap": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Bitmap index type",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultBitmapType,
+							ValidateFunc: validation.StringInSlice([]string{"roaring", "concise"}, false),
+							Description:  "Bitmap type (roaring or concise)",
+						},
+						"compress_run_on_serialization": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether runs are compressed when bitmaps are written (roaring)",
+						},
+					},
+				},
+			},
+			"dimension_compression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(dimensionCompressions, false),
+				Description:  "Dimension compression (lz4, lzf, zstd or uncompressed). Druid defaults to lz4",
+			},
+			"string_dictionary_encoding": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Encoding of string dimension dictionaries",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"utf8", frontCodedEncodingType}, false),
+							Description:  "Encoding type (utf8 or frontCoded)",
+						},
+						"bucket_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validatePowerOfTwo(1, 128),
+							Description:  "Values per front-coded bucket, a power of 2 up to 128. Druid defaults to 4 (frontCoded)",
+						},
+						"format_version": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(0, 1),
+							Description:  "Front coding format version, 0 or 1. Druid defaults to 0 (frontCoded)",
+						},
+					},
+				},
+			},
+			"metric_compression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(metricCompressions, false),
+				Description:  "Numeric metric compression (lz4, lzf, zstd, uncompressed or none). Druid defaults to lz4",
+			},
+			"long_encoding": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"longs", "auto"}, false),
+				Description:  "Encoding of long columns (longs or auto). Druid defaults to longs",
+			},
+			"complex_metric_compression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(metricCompressions, false),
+				Description:  "Compression of complex metrics such as sketches (lz4, lzf, zstd, uncompressed or none)",
+			},
+			"json_compression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(metricCompressions, false),
+				Description:  "Compression of nested json columns (lz4, lzf, zstd, uncompressed or none)",
+			},
+		},
+	}
+}
+
+// indexSpecStringFields maps the string index spec attributes to their Druid
+// keys.
+var indexSpecStringFields = map[string]string{
+	"dimension_compression":      "dimensionCompression",
+	"metric_compression":         "metricCompression",
+	"long_encoding":              "longEncoding",
+	"complex_metric_compression": "complexMetricCompression",
+	"json_compression":           "jsonCompression",
+}
+
+// buildIndexSpec converts an index spec block into a Druid indexSpec, or
+// returns nil when it sets nothing.
+func buildIndexSpec(indexSpec map[string]interface{}) map[string]interface{} {
+	is := map[string]interface{}{}
+
+	if bitmap := firstBlock(indexSpec["bitmap"]); bitmap != nil {
+		b := map[string]interface{}{
+			"type": bitmap["type"].(string),
+		}
+		if compress := bitmap["compress_run_on_serialization"].(bool); b["type"] == "roaring" && !compress {
+			b["compressRunOnSerialization"] = compress
+		}
+		is["bitmap"] = b
+	}
+
+	if encoding := firstBlock(indexSpec["string_dictionary_encoding"]); encoding != nil {
+		e := map[string]interface{}{
+			"type": encoding["type"].(string),
+		}
+		if e["type"] == frontCodedEncodingType {
+			if bucketSize := encoding["bucket_size"].(int); bucketSize > 0 {
+				e["bucketSize"] = bucketSize
+			}
+			if formatVersion := encoding["format_version"].(int); formatVersion > 0 {
+				e["formatVersion"] = formatVersion
+			}
+		}
+		is["stringDictionaryEncoding"] = e
+	}
+
+	for key, druidKey := range indexSpecStringFields {
+		if v := indexSpec[key].(string); v != "" {
+			is[druidKey] = v
+		}
+	}
+
+	if len(is) == 0 {
+		return nil
+	}
+	return is
+}
+
+// validateIndexSpec checks that type-specific options match the bitmap and
+// string dictionary encoding types.
+func validateIndexSpec(indexSpec map[string]interface{}, path string) error {
+	if bitmap := firstBlock(indexSpec["bitmap"]); bitmap != nil {
+		compress, _ := bitmap["compress_run_on_serialization"].(bool)
+		if bitmap["type"] == "concise" && !compress {
+			return fmt.Errorf("%s.bitmap.0: compress_run_on_serialization is only supported by roaring bitmaps", path)
+		}
+	}
+
+	if encoding := firstBlock(indexSpec["string_dictionary_encoding"]); encoding != nil && encoding["type"] != frontCodedEncodingType {
+		for _, key := range []string{"bucket_size", "format_version"} {
+			if v, _ := encoding[key].(int); v > 0 {
+				return fmt.Errorf("%s.string_dictionary_encoding.0: %s is only supported by the frontCoded encoding", path, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// flattenIndexSpec converts a Druid indexSpec back into an index spec block.
+// Druid's defaults are dropped unless prior sets them.
+func flattenIndexSpec(is map[string]interface{}, prior map[string]interface{}) []interface{} {
+	result := map[string]interface{}{
+		"bitmap":                     []interface{}{},
+		"string_dictionary_encoding": []interface{}{},
+	}
+
+	if bitmap, ok := is["bitmap"].(map[string]interface{}); ok {
+		bitmapType := flattenString(bitmap["type"])
+		if bitmapType == "" {
+			bitmapType = defaultBitmapType
+		}
+		compress := true
+		if v, ok := bitmap["compressRunOnSerialization"]; ok && v != nil {
+			compress = flattenBool(v)
+		}
+		if isSet(prior["bitmap"]) || bitmapType != defaultBitmapType || !compress {
+			result["bitmap"] = []interface{}{
+				map[string]interface{}{
+					"type":                          bitmapType,
+					"compress_run_on_serialization": compress,
+				},
+			}
+		}
+	}
+
+	if encoding, ok := is["stringDictionaryEncoding"].(map[string]interface{}); ok {
+		encodingType := flattenString(encoding["type"])
+		if isSet(prior["string_dictionary_encoding"]) || (encodingType != "" && encodingType != defaultStringEncoding) {
+			priorEncoding := firstBlock(prior["string_dictionary_encoding"])
+			e := map[string]interface{}{
+				"type":           encodingType,
+				"bucket_size":    0,
+				"format_version": 0,
+			}
+			if encodingType == frontCodedEncodingType {
+				e["bucket_size"] = flattenDefaultedInt(encoding["bucketSize"], defaultFrontCodedSize, priorEncoding["bucket_size"])
+				e["format_version"] = flattenInt(encoding["formatVersion"])
+			}
+			result["string_dictionary_encoding"] = []interface{}{e}
+		}
+	}
+
+	defaults := map[string]string{
+		"dimensionCompression": defaultCompression,
+		"metricCompression":    defaultCompression,
+		"longEncoding":         defaultLongEncoding,
+	}
+	for key, druidKey := range indexSpecStringFields {
+		result[key] = flattenDefaulted(is[druidKey], defaults[druidKey], prior[key])
+	}
+
+	return []interface{}{result}
+}
+
+// isDefaultIndexSpec reports whether a flattened index spec block leaves every
+// option to Druid's defaults.
+func isDefaultIndexSpec(block []interface{}) bool {
+	for _, v := range firstBlock(block) {
+		switch v := v.(type) {
+		case string:
+			if v != "" {
+				return false
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// upgradeIndexSpecBitmapV0 converts a version 0 bitmap string map into a
+// bitmap block.
+func upgradeIndexSpecBitmapV0(v interface{}) []interface{} {
+	bitmap, ok := v.(map[string]interface{})
+	if !ok || len(bitmap) == 0 {
+		return []interface{}{}
+	}
+
+	bitmapType := flattenString(bitmap["type"])
+	if bitmapType == "" {
+		bitmapType = defaultBitmapType
+	}
+	compress := true
+	if v, ok := bitmap["compressRunOnSerialization"]; ok {
+		compress = flattenBool(v)
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"type":                          bitmapType,
+			"compress_run_on_serialization": compress,
+		},
+	}
+}
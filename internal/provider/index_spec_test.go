@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIndexSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "roaring bitmap without run compression",
+			input: map[string]interface{}{
+				"bitmap": []interface{}{
+					map[string]interface{}{"type": "roaring", "compress_run_on_serialization": false},
+				},
+			},
+			expected: map[string]interface{}{
+				"bitmap": map[string]interface{}{"type": "roaring", "compressRunOnSerialization": false},
+			},
+		},
+		{
+			name: "concise bitmap",
+			input: map[string]interface{}{
+				"bitmap": []interface{}{
+					map[string]interface{}{"type": "concise"},
+				},
+			},
+			expected: map[string]interface{}{
+				"bitmap": map[string]interface{}{"type": "concise"},
+			},
+		},
+		{
+			name: "front coded strings",
+			input: map[string]interface{}{
+				"string_dictionary_encoding": []interface{}{
+					map[string]interface{}{"type": "frontCoded", "bucket_size": 16, "format_version": 1},
+				},
+			},
+			expected: map[string]interface{}{
+				"stringDictionaryEncoding": map[string]interface{}{"type": "frontCoded", "bucketSize": 16, "formatVersion": 1},
+			},
+		},
+		{
+			name: "column encodings and compressions",
+			input: map[string]interface{}{
+				"dimension_compression":      "zstd",
+				"metric_compression":         "none",
+				"long_encoding":              "auto",
+				"complex_metric_compression": "lz4",
+				"json_compression":           "uncompressed",
+			},
+			expected: map[string]interface{}{
+				"dimensionCompression":     "zstd",
+				"metricCompression":        "none",
+				"longEncoding":             "auto",
+				"complexMetricCompression": "lz4",
+				"jsonCompression":          "uncompressed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"tuning_config": []interface{}{
+					map[string]interface{}{
+						"index_spec":                           []interface{}{tt.input},
+						"index_spec_for_intermediate_persists": []interface{}{tt.input},
+					},
+				},
+			})
+			tc := buildTuningConfig(d)
+			assert.Equal(t, tt.expected, tc["indexSpec"])
+			assert.Equal(t, tt.expected, tc["indexSpecForIntermediatePersists"])
+		})
+	}
+}
+
+func TestValidateIndexSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		indexSpec map[string]interface{}
+		wantErr   string
+	}{
+		{
+			name: "roaring without run compression",
+			indexSpec: map[string]interface{}{
+				"bitmap": []interface{}{
+					map[string]interface{}{"type": "roaring", "compress_run_on_serialization": false},
+				},
+			},
+		},
+		{
+			name: "front coded with bucket size",
+			indexSpec: map[string]interface{}{
+				"string_dictionary_encoding": []interface{}{
+					map[string]interface{}{"type": "frontCoded", "bucket_size": 8, "format_version": 1},
+				},
+			},
+		},
+		{
+			name: "concise without run compression",
+			indexSpec: map[string]interface{}{
+				"bitmap": []interface{}{
+					map[string]interface{}{"type": "concise", "compress_run_on_serialization": false},
+				},
+			},
+			wantErr: "tuning_config.0.index_spec.0.bitmap.0: compress_run_on_serialization is only supported by roaring bitmaps",
+		},
+		{
+			name: "utf8 with bucket size",
+			indexSpec: map[string]interface{}{
+				"string_dictionary_encoding": []interface{}{
+					map[string]interface{}{"type": "utf8", "bucket_size": 8, "format_version": 0},
+				},
+			},
+			wantErr: "tuning_config.0.index_spec.0.string_dictionary_encoding.0: bucket_size is only supported by the frontCoded encoding",
+		},
+		{
+			name: "utf8 with format version",
+			indexSpec: map[string]interface{}{
+				"string_dictionary_encoding": []interface{}{
+					map[string]interface{}{"type": "utf8", "bucket_size": 0, "format_version": 1},
+				},
+			},
+			wantErr: "format_version is only supported by the frontCoded encoding",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIndexSpec(tt.indexSpec, "tuning_config.0.index_spec.0")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("intermediate persists are validated", func(t *testing.T) {
+		err := validateTuningConfig(map[string]interface{}{
+			"index_spec_for_intermediate_persists": []interface{}{
+				map[string]interface{}{
+					"bitmap": []interface{}{
+						map[string]interface{}{"type": "concise", "compress_run_on_serialization": false},
+					},
+				},
+			},
+		}, func(string) bool { return false }, "tuning_config.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tuning_config.0.index_spec_for_intermediate_persists.0.bitmap.0")
+	})
+}
+
+func TestIndexSpecSchemaValidation(t *testing.T) {
+	config := func(indexSpec map[string]interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"datasource": "test-datasource",
+			"timestamp_spec": []interface{}{
+				map[string]interface{}{"column": "__time"},
+			},
+			"topic":               "test-topic",
+			"input_format":        []interface{}{map[string]interface{}{"type": "json"}},
+			"consumer_properties": map[string]interface{}{"bootstrap.servers": "localhost:9092"},
+			"tuning_config": []interface{}{
+				map[string]interface{}{"index_spec": []interface{}{indexSpec}},
+			},
+		})
+	}
+
+	valid := []map[string]interface{}{
+		{"bitmap": []interface{}{map[string]interface{}{"type": "concise"}}},
+		{"string_dictionary_encoding": []interface{}{map[string]interface{}{"type": "frontCoded", "bucket_size": 128}}},
+		{"metric_compression": "none", "long_encoding": "auto", "json_compression": "zstd"},
+	}
+	for _, indexSpec := range valid {
+		assert.False(t, resourceKafkaSupervisor().Validate(config(indexSpec)).HasError(), indexSpec)
+	}
+
+	invalid := []map[string]interface{}{
+		{"bitmap": []interface{}{map[string]interface{}{"type": "bitset"}}},
+		{"string_dictionary_encoding": []interface{}{map[string]interface{}{"type": "frontCoded", "bucket_size": 12}}},
+		{"string_dictionary_encoding": []interface{}{map[string]interface{}{"type": "frontCoded", "bucket_size": 256}}},
+		{"string_dictionary_encoding": []interface{}{map[string]interface{}{"type": "frontCoded", "format_version": 2}}},
+		{"string_dictionary_encoding": []interface{}{map[string]interface{}{"type": "dictionary"}}},
+		{"dimension_compression": "none"},
+		{"metric_compression": "gzip"},
+		{"long_encoding": "delta"},
+		{"complex_metric_compression": "snappy"},
+		{"json_compression": "LZ4"},
+	}
+	for _, indexSpec := range invalid {
+		assert.True(t, resourceKafkaSupervisor().Validate(config(indexSpec)).HasError(), indexSpec)
+	}
+}
+
+func TestFlattenIndexSpec(t *testing.T) {
+	// An indexSpec as returned by Druid, with defaults filled in
+	druidIndexSpec := map[string]interface{}{
+		"bitmap":                   map[string]interface{}{"type": "roaring"},
+		"dimensionCompression":     "lz4",
+		"stringDictionaryEncoding": map[string]interface{}{"type": "utf8"},
+		"metricCompression":        "lz4",
+		"longEncoding":             "longs",
+		"jsonCompression":          "zstd",
+	}
+
+	t.Run("defaults are dropped", func(t *testing.T) {
+		result := flattenIndexSpec(druidIndexSpec, nil)
+		require.Len(t, result, 1)
+		is := result[0].(map[string]interface{})
+		assert.Empty(t, is["bitmap"])
+		assert.Empty(t, is["string_dictionary_encoding"])
+		assert.Equal(t, "", is["dimension_compression"])
+		assert.Equal(t, "", is["metric_compression"])
+		assert.Equal(t, "", is["long_encoding"])
+		assert.Equal(t, "", is["complex_metric_compression"])
+		assert.Equal(t, "zstd", is["json_compression"])
+	})
+
+	t.Run("defaults set in prior are kept", func(t *testing.T) {
+		result := flattenIndexSpec(druidIndexSpec, map[string]interface{}{
+			"bitmap":                     []interface{}{map[string]interface{}{"type": "roaring"}},
+			"string_dictionary_encoding": []interface{}{map[string]interface{}{"type": "utf8"}},
+			"long_encoding":              "longs",
+		})
+		is := result[0].(map[string]interface{})
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "roaring", "compress_run_on_serialization": true},
+		}, is["bitmap"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "utf8", "bucket_size": 0, "format_version": 0},
+		}, is["string_dictionary_encoding"])
+		assert.Equal(t, "longs", is["long_encoding"])
+	})
+
+	t.Run("non-default types are read back", func(t *testing.T) {
+		result := flattenIndexSpec(map[string]interface{}{
+			"bitmap":                   map[string]interface{}{"type": "roaring", "compressRunOnSerialization": false},
+			"stringDictionaryEncoding": map[string]interface{}{"type": "frontCoded", "bucketSize": float64(4), "formatVersion": float64(1)},
+			"longEncoding":             "auto",
+		}, nil)
+		is := result[0].(map[string]interface{})
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "roaring", "compress_run_on_serialization": false},
+		}, is["bitmap"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "frontCoded", "bucket_size": 0, "format_version": 1},
+		}, is["string_dictionary_encoding"])
+		assert.Equal(t, "auto", is["long_encoding"])
+
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+		require.NoError(t, d.Set("tuning_config", []interface{}{
+			map[string]interface{}{"index_spec": result},
+		}))
+	})
+}
+
+func TestResourceKafkaSupervisorStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"datasource": "test-datasource",
+		"tuning_config": []interface{}{
+			map[string]interface{}{
+				"index_spec": []interface{}{
+					map[string]interface{}{
+						"bitmap":                map[string]interface{}{"type": "concise"},
+						"dimension_compression": "lz4",
+					},
+				},
+				"index_spec_for_intermediate_persists": []interface{}{
+					map[string]interface{}{
+						"bitmap": map[string]interface{}{"compressRunOnSerialization": "false"},
+					},
+				},
+			},
+		},
+	}
+
+	upgraded, err := resourceKafkaSupervisorStateUpgradeV0(context.Background(), rawState, nil)
+	require.NoError(t, err)
+
+	tc := upgraded["tuning_config"].([]interface{})[0].(map[string]interface{})
+	indexSpec := tc["index_spec"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "concise", "compress_run_on_serialization": true},
+	}, indexSpec["bitmap"])
+	assert.Equal(t, "lz4", indexSpec["dimension_compression"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "roaring", "compress_run_on_serialization": false},
+	}, tc["index_spec_for_intermediate_persists"].([]interface{})[0].(map[string]interface{})["bitmap"])
+
+	// An empty or missing bitmap map becomes an empty block list
+	assert.Equal(t, []interface{}{}, upgradeIndexSpecBitmapV0(nil))
+	assert.Equal(t, []interface{}{}, upgradeIndexSpecBitmapV0(map[string]interface{}{}))
+
+	// States without a tuning_config are left alone
+	upgraded, err = resourceKafkaSupervisorStateUpgradeV0(context.Background(), map[string]interface{}{"datasource": "test-datasource"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"datasource": "test-datasource"}, upgraded)
+
+	// The upgraded state decodes with the current schema
+	r := resourceKafkaSupervisor()
+	assert.Equal(t, 1, r.SchemaVersion)
+	assert.True(t, r.StateUpgraders[0].Type.IsObjectType())
+	assert.True(t, r.StateUpgraders[0].Type.AttributeType("tuning_config").ElementType().AttributeType("index_spec").ElementType().AttributeType("bitmap").IsMapType())
+}
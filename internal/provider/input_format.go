@@ -0,0 +1,911 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Defaults Druid fills in for the kafka and tsv input formats
+const (
+	defaultHeaderColumnPrefix  = "kafka.header."
+	defaultKeyColumnName       = "kafka.key"
+	defaultTimestampColumnName = "kafka.timestamp"
+	defaultTopicColumnName     = "kafka.topic"
+	defaultHeaderEncoding      = "UTF-8"
+	defaultTSVDelimiter        = "\t"
+)
+
+// inputFormatSchema returns the schema of the input format block at path. The
+// top-level format can be the kafka format, which wraps further input formats
+// for the record value and key.
+func inputFormatSchema(path string, kafka bool) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"type": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Input format type (json, csv, tsv, etc.)",
+		},
+		"csv": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{path + ".tsv", path + ".regex"},
+			Description:   "Options of the csv input format",
+			Elem:          delimitedFormatSchema(false),
+		},
+		"tsv": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{path + ".csv", path + ".regex"},
+			Description:   "Options of the tsv input format",
+			Elem:          delimitedFormatSchema(true),
+		},
+		"regex": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{path + ".csv", path + ".tsv"},
+			Description:   "Options of the regex input format",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"pattern": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringIsNotEmpty,
+						Description:  "Regular expression whose capture groups are the columns",
+					},
+					"columns": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Names of the capture groups, in order",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"list_delimiter": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Delimiter of multi-value columns",
+					},
+				},
+			},
+		},
+		"avro_bytes_decoder": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "How Avro schemas are resolved (avro_stream)",
+			Elem:        avroBytesDecoderSchema(),
+		},
+		"binary_as_string": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether Avro bytes columns are read as UTF-8 strings (avro_stream)",
+		},
+		"extract_unions_by_type": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether Avro unions are extracted into one field per member type (avro_stream)",
+		},
+		"proto_bytes_decoder": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "How Protobuf message descriptors are resolved (protobuf)",
+			Elem:        protoBytesDecoderSchema(),
+		},
+		"flatten_spec": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Flatten spec for nested input such as JSON",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"use_field_discovery": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Whether to read top-level fields that are not listed in fields",
+					},
+					"fields": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Fields to extract from the nested input",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"type": {
+									Type:         schema.TypeString,
+									Required:     true,
+									ValidateFunc: validation.StringInSlice([]string{"root", "path", "jq", "tree"}, false),
+									Description:  "Field type (root, path, jq, tree)",
+								},
+								"name": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "Name of the extracted field",
+								},
+								"expr": {
+									Type:        schema.TypeString,
+									Optional:    true,
+									Description: "JsonPath or jq expression (path, jq)",
+								},
+								"nodes": {
+									Type:        schema.TypeList,
+									Optional:    true,
+									Description: "Field names leading to the value (tree)",
+									Elem:        &schema.Schema{Type: schema.TypeString},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if kafka {
+		s["value_format"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Input format of the record value (kafka)",
+			Elem:        inputFormatSchema(path+".value_format.0", false),
+		}
+		s["key_format"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Input format of the record key, which is ingested into key_column_name (kafka)",
+			Elem:        inputFormatSchema(path+".key_format.0", false),
+		}
+		s["header_format"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "How record headers are decoded; headers are not ingested without it (kafka)",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "string",
+						ValidateFunc: validation.StringInSlice([]string{"string"}, false),
+						Description:  "Header format type",
+					},
+					"encoding": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Character set of the header values, defaults to UTF-8",
+					},
+				},
+			},
+		}
+		s["header_column_prefix"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Prefix of the columns holding record headers, defaults to kafka.header. (kafka)",
+		}
+		s["key_column_name"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Column holding the record key, defaults to kafka.key (kafka)",
+		}
+		s["timestamp_column_name"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Column holding the record timestamp, defaults to kafka.timestamp (kafka)",
+		}
+		s["topic_column_name"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Column holding the record topic, defaults to kafka.topic (kafka)",
+		}
+	}
+
+	return &schema.Resource{Schema: s}
+}
+
+// delimitedFormatSchema returns the options of the csv and tsv input formats.
+func delimitedFormatSchema(tsv bool) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"columns": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Column names, in order. Required unless find_columns_from_header is set",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"find_columns_from_header": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether column names are read from the first row after the skipped header rows",
+		},
+		"skip_header_rows": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Number of rows to skip at the start of each message",
+		},
+		"list_delimiter": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Delimiter of multi-value columns",
+		},
+	}
+
+	if tsv {
+		s["delimiter"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Column delimiter, defaults to a tab",
+		}
+	}
+
+	return &schema.Resource{Schema: s}
+}
+
+// buildInputFormat converts an input_format block into a Druid inputFormat.
+func buildInputFormat(inputFormat map[string]interface{}) map[string]interface{} {
+	inputFormatConfig := map[string]interface{}{
+		"type": inputFormat["type"].(string),
+	}
+
+	// Delimited and regex formats
+	for _, key := range []string{"csv", "tsv", "regex"} {
+		options := firstBlock(inputFormat[key])
+		if options == nil {
+			continue
+		}
+		if columns, _ := options["columns"].([]interface{}); len(columns) > 0 {
+			inputFormatConfig["columns"] = columns
+		}
+		if findColumns, _ := options["find_columns_from_header"].(bool); findColumns {
+			inputFormatConfig["findColumnsFromHeader"] = findColumns
+		}
+		if skipRows, _ := options["skip_header_rows"].(int); skipRows > 0 {
+			inputFormatConfig["skipHeaderRows"] = skipRows
+		}
+		if listDelimiter, _ := options["list_delimiter"].(string); listDelimiter != "" {
+			inputFormatConfig["listDelimiter"] = listDelimiter
+		}
+		if delimiter, _ := options["delimiter"].(string); delimiter != "" {
+			inputFormatConfig["delimiter"] = delimiter
+		}
+		if pattern, _ := options["pattern"].(string); pattern != "" {
+			inputFormatConfig["pattern"] = pattern
+		}
+	}
+
+	if decoder := firstBlock(inputFormat["avro_bytes_decoder"]); decoder != nil {
+		inputFormatConfig["avroBytesDecoder"] = buildAvroBytesDecoder(decoder)
+	}
+	if binaryAsString := inputFormat["binary_as_string"].(bool); binaryAsString {
+		inputFormatConfig["binaryAsString"] = binaryAsString
+	}
+	if extractUnions := inputFormat["extract_unions_by_type"].(bool); extractUnions {
+		inputFormatConfig["extractUnionsByType"] = extractUnions
+	}
+	if decoder := firstBlock(inputFormat["proto_bytes_decoder"]); decoder != nil {
+		inputFormatConfig["protoBytesDecoder"] = buildProtoBytesDecoder(decoder)
+	}
+
+	if flattenSpec := firstBlock(inputFormat["flatten_spec"]); flattenSpec != nil {
+		inputFormatConfig["flattenSpec"] = buildFlattenSpec(flattenSpec)
+	}
+
+	// Kafka input format
+	if valueFormat := firstBlock(inputFormat["value_format"]); valueFormat != nil {
+		inputFormatConfig["valueFormat"] = buildInputFormat(valueFormat)
+	}
+	if keyFormat := firstBlock(inputFormat["key_format"]); keyFormat != nil {
+		inputFormatConfig["keyFormat"] = buildInputFormat(keyFormat)
+	}
+	if headerFormat := firstBlock(inputFormat["header_format"]); headerFormat != nil {
+		hf := map[string]interface{}{
+			"type": headerFormat["type"].(string),
+		}
+		if encoding := headerFormat["encoding"].(string); encoding != "" {
+			hf["encoding"] = encoding
+		}
+		inputFormatConfig["headerFormat"] = hf
+	}
+	for key, druidKey := range map[string]string{
+		"header_column_prefix":  "headerColumnPrefix",
+		"key_column_name":       "keyColumnName",
+		"timestamp_column_name": "timestampColumnName",
+		"topic_column_name":     "topicColumnName",
+	} {
+		if v, _ := inputFormat[key].(string); v != "" {
+			inputFormatConfig[druidKey] = v
+		}
+	}
+
+	return inputFormatConfig
+}
+
+// flattenInputFormat reads the input format back. prior is the input format
+// currently in state, if any, and kafka whether the block has the kafka
+// format attributes. Druid returns a default flattenSpec for nested
+// formats and default column names for the kafka format, so these are only
+// kept when they differ from the defaults or are already managed.
+func flattenInputFormat(inputFormat map[string]interface{}, prior map[string]interface{}, kafka bool) []interface{} {
+	result := map[string]interface{}{
+		"type":                   flattenString(inputFormat["type"]),
+		"csv":                    []interface{}{},
+		"tsv":                    []interface{}{},
+		"regex":                  []interface{}{},
+		"avro_bytes_decoder":     []interface{}{},
+		"binary_as_string":       flattenBool(inputFormat["binaryAsString"]),
+		"extract_unions_by_type": flattenBool(inputFormat["extractUnionsByType"]),
+		"proto_bytes_decoder":    []interface{}{},
+		"flatten_spec":           []interface{}{},
+	}
+
+	if abd, ok := inputFormat["avroBytesDecoder"].(map[string]interface{}); ok {
+		result["avro_bytes_decoder"] = flattenAvroBytesDecoder(abd)
+	}
+	if pbd, ok := inputFormat["protoBytesDecoder"].(map[string]interface{}); ok {
+		result["proto_bytes_decoder"] = flattenProtoBytesDecoder(pbd)
+	}
+
+	switch formatType := flattenString(inputFormat["type"]); formatType {
+	case "csv", "tsv":
+		options := map[string]interface{}{
+			"columns":                  flattenStringList(inputFormat["columns"]),
+			"find_columns_from_header": flattenBool(inputFormat["findColumnsFromHeader"]),
+			"skip_header_rows":         flattenInt(inputFormat["skipHeaderRows"]),
+			"list_delimiter":           flattenString(inputFormat["listDelimiter"]),
+		}
+		if formatType == "tsv" {
+			options["delimiter"] = flattenDefaulted(inputFormat["delimiter"], defaultTSVDelimiter, firstBlock(prior["tsv"])["delimiter"])
+		}
+		result[formatType] = []interface{}{options}
+	case "regex":
+		result["regex"] = []interface{}{
+			map[string]interface{}{
+				"pattern":        flattenString(inputFormat["pattern"]),
+				"columns":        flattenStringList(inputFormat["columns"]),
+				"list_delimiter": flattenString(inputFormat["listDelimiter"]),
+			},
+		}
+	}
+
+	if fs, ok := inputFormat["flattenSpec"].(map[string]interface{}); ok {
+		useFieldDiscovery := true
+		if v, ok := fs["useFieldDiscovery"]; ok && v != nil {
+			useFieldDiscovery = flattenBool(v)
+		}
+		fields := []interface{}{}
+		if list, ok := fs["fields"].([]interface{}); ok {
+			for _, field := range list {
+				if f, ok := field.(map[string]interface{}); ok {
+					fields = append(fields, map[string]interface{}{
+						"type":  flattenString(f["type"]),
+						"name":  flattenString(f["name"]),
+						"expr":  flattenString(f["expr"]),
+						"nodes": flattenStringList(f["nodes"]),
+					})
+				}
+			}
+		}
+		if isSet(prior["flatten_spec"]) || !useFieldDiscovery || len(fields) > 0 {
+			result["flatten_spec"] = []interface{}{
+				map[string]interface{}{
+					"use_field_discovery": useFieldDiscovery,
+					"fields":              fields,
+				},
+			}
+		}
+	}
+
+	if kafka {
+		result["value_format"] = []interface{}{}
+		if vf, ok := inputFormat["valueFormat"].(map[string]interface{}); ok {
+			result["value_format"] = flattenInputFormat(vf, firstBlock(prior["value_format"]), false)
+		}
+		result["key_format"] = []interface{}{}
+		if kf, ok := inputFormat["keyFormat"].(map[string]interface{}); ok {
+			result["key_format"] = flattenInputFormat(kf, firstBlock(prior["key_format"]), false)
+		}
+
+		result["header_format"] = []interface{}{}
+		if hf, ok := inputFormat["headerFormat"].(map[string]interface{}); ok {
+			headerFormat := map[string]interface{}{
+				"type":     "string",
+				"encoding": flattenDefaulted(hf["encoding"], defaultHeaderEncoding, firstBlock(prior["header_format"])["encoding"]),
+			}
+			if t := flattenString(hf["type"]); t != "" {
+				headerFormat["type"] = t
+			}
+			result["header_format"] = []interface{}{headerFormat}
+		}
+
+		result["header_column_prefix"] = flattenDefaulted(inputFormat["headerColumnPrefix"], defaultHeaderColumnPrefix, prior["header_column_prefix"])
+		result["key_column_name"] = flattenDefaulted(inputFormat["keyColumnName"], defaultKeyColumnName, prior["key_column_name"])
+		result["timestamp_column_name"] = flattenDefaulted(inputFormat["timestampColumnName"], defaultTimestampColumnName, prior["timestamp_column_name"])
+		result["topic_column_name"] = flattenDefaulted(inputFormat["topicColumnName"], defaultTopicColumnName, prior["topic_column_name"])
+	}
+
+	return []interface{}{result}
+}
+
+func buildFlattenSpec(flattenSpec map[string]interface{}) map[string]interface{} {
+	fs := map[string]interface{}{
+		"useFieldDiscovery": flattenSpec["use_field_discovery"].(bool),
+	}
+
+	if fields := flattenSpec["fields"].([]interface{}); len(fields) > 0 {
+		var fieldSpecs []interface{}
+		for _, field := range fields {
+			fieldMap := field.(map[string]interface{})
+			f := map[string]interface{}{
+				"type": fieldMap["type"].(string),
+				"name": fieldMap["name"].(string),
+			}
+			if expr := fieldMap["expr"].(string); expr != "" {
+				f["expr"] = expr
+			}
+			if nodes := fieldMap["nodes"].([]interface{}); len(nodes) > 0 {
+				f["nodes"] = nodes
+			}
+			fieldSpecs = append(fieldSpecs, f)
+		}
+		fs["fields"] = fieldSpecs
+	}
+
+	return fs
+}
+
+// validateFlattenSpec checks that each field sets the attributes its type
+// requires.
+func validateFlattenSpec(flattenSpec map[string]interface{}, path string) error {
+	fields, _ := flattenSpec["fields"].([]interface{})
+	for i, field := range fields {
+		fieldMap, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldType, _ := fieldMap["type"].(string)
+		expr, _ := fieldMap["expr"].(string)
+		nodes, _ := fieldMap["nodes"].([]interface{})
+
+		switch fieldType {
+		case "path", "jq":
+			if expr == "" {
+				return fmt.Errorf("%s.fields.%d: %s field requires expr", path, i, fieldType)
+			}
+		case "tree":
+			if len(nodes) == 0 {
+				return fmt.Errorf("%s.fields.%d: tree field requires nodes", path, i)
+			}
+		case "root":
+			if expr != "" || len(nodes) > 0 {
+				return fmt.Errorf("%s.fields.%d: root field does not take expr or nodes", path, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+var avroBytesDecoderTypes = []string{"schema_registry", "schema_repo", "schema_inline"}
+
+// avroBytesDecoderSchema returns the schema of an avroBytesDecoder. The
+// attributes used depend on the decoder type.
+func avroBytesDecoderSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(avroBytesDecoderTypes, false),
+				Description:  "Decoder type (schema_registry, schema_repo, schema_inline)",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "Schema registry or schema repository URL (schema_registry, schema_repo)",
+			},
+			"urls": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Schema registry URLs (schema_registry)",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+			},
+			"capacity": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of schemas cached (schema_registry)",
+			},
+			"config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Schema registry client configuration, such as basic auth credentials (schema_registry)",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "HTTP headers sent to the schema registry (schema_registry)",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"topic": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Topic used as the schema repository subject (schema_repo)",
+			},
+			"schema": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+				Description:      "Avro schema as JSON (schema_inline)",
+			},
+		},
+	}
+}
+
+// buildAvroBytesDecoder converts an avro_bytes_decoder block into a Druid
+// avroBytesDecoder.
+func buildAvroBytesDecoder(decoder map[string]interface{}) map[string]interface{} {
+	decoderType := decoder["type"].(string)
+	abd := map[string]interface{}{
+		"type": decoderType,
+	}
+
+	switch decoderType {
+	case "schema_registry":
+		buildSchemaRegistryDecoder(decoder, abd)
+	case "schema_repo":
+		abd["subjectAndIdConverter"] = map[string]interface{}{
+			"type":  "avro_1124",
+			"topic": decoder["topic"].(string),
+		}
+		abd["schemaRepository"] = map[string]interface{}{
+			"type": "avro_1124_rest_client",
+			"url":  decoder["url"].(string),
+		}
+	case "schema_inline":
+		var avroSchema interface{}
+		if err := json.Unmarshal([]byte(decoder["schema"].(string)), &avroSchema); err == nil {
+			abd["schema"] = avroSchema
+		}
+	}
+
+	return abd
+}
+
+// flattenAvroBytesDecoder converts a Druid avroBytesDecoder back into an
+// avro_bytes_decoder block.
+func flattenAvroBytesDecoder(abd map[string]interface{}) []interface{} {
+	decoder := map[string]interface{}{
+		"type":   flattenString(abd["type"]),
+		"topic":  "",
+		"schema": "",
+	}
+	flattenSchemaRegistryDecoder(abd, decoder)
+
+	if converter, ok := abd["subjectAndIdConverter"].(map[string]interface{}); ok {
+		decoder["topic"] = flattenString(converter["topic"])
+	}
+	if repository, ok := abd["schemaRepository"].(map[string]interface{}); ok {
+		decoder["url"] = flattenString(repository["url"])
+	}
+	if avroSchema, ok := abd["schema"]; ok && avroSchema != nil {
+		if b, err := json.Marshal(avroSchema); err == nil {
+			decoder["schema"] = string(b)
+		}
+	}
+
+	return []interface{}{decoder}
+}
+
+var protoBytesDecoderTypes = []string{"file", "schema_registry"}
+
+// protoBytesDecoderSchema returns the schema of a protoBytesDecoder. The
+// attributes used depend on the decoder type.
+func protoBytesDecoderSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(protoBytesDecoderTypes, false),
+				Description:  "Decoder type (file, schema_registry)",
+			},
+			"descriptor": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "URL or path of the compiled descriptor file (file)",
+			},
+			"proto_message_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Fully qualified message type, defaults to the first type in the descriptor (file)",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "Schema registry URL (schema_registry)",
+			},
+			"urls": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Schema registry URLs (schema_registry)",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+			},
+			"capacity": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of schemas cached (schema_registry)",
+			},
+			"config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Schema registry client configuration, such as basic auth credentials (schema_registry)",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "HTTP headers sent to the schema registry (schema_registry)",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// buildProtoBytesDecoder converts a proto_bytes_decoder block into a Druid
+// protoBytesDecoder.
+func buildProtoBytesDecoder(decoder map[string]interface{}) map[string]interface{} {
+	decoderType := decoder["type"].(string)
+	pbd := map[string]interface{}{
+		"type": decoderType,
+	}
+
+	switch decoderType {
+	case "file":
+		pbd["descriptor"] = decoder["descriptor"].(string)
+		if messageType := decoder["proto_message_type"].(string); messageType != "" {
+			pbd["protoMessageType"] = messageType
+		}
+	case "schema_registry":
+		buildSchemaRegistryDecoder(decoder, pbd)
+	}
+
+	return pbd
+}
+
+// flattenProtoBytesDecoder converts a Druid protoBytesDecoder back into a
+// proto_bytes_decoder block.
+func flattenProtoBytesDecoder(pbd map[string]interface{}) []interface{} {
+	decoder := map[string]interface{}{
+		"type":               flattenString(pbd["type"]),
+		"descriptor":         flattenString(pbd["descriptor"]),
+		"proto_message_type": flattenString(pbd["protoMessageType"]),
+	}
+	flattenSchemaRegistryDecoder(pbd, decoder)
+
+	return []interface{}{decoder}
+}
+
+// buildSchemaRegistryDecoder copies the Schema Registry attributes that are
+// set from a decoder block into a Druid decoder.
+func buildSchemaRegistryDecoder(decoder, result map[string]interface{}) {
+	if url := decoder["url"].(string); url != "" {
+		result["url"] = url
+	}
+	if urls := decoder["urls"].([]interface{}); len(urls) > 0 {
+		result["urls"] = urls
+	}
+	if capacity := decoder["capacity"].(int); capacity > 0 {
+		result["capacity"] = capacity
+	}
+	if config := decoder["config"].(map[string]interface{}); len(config) > 0 {
+		result["config"] = config
+	}
+	if headers := decoder["headers"].(map[string]interface{}); len(headers) > 0 {
+		result["headers"] = headers
+	}
+}
+
+// flattenSchemaRegistryDecoder reads the Schema Registry attributes of a
+// Druid decoder back into a decoder block.
+func flattenSchemaRegistryDecoder(decoder, result map[string]interface{}) {
+	result["url"] = flattenString(decoder["url"])
+	result["urls"] = flattenStringList(decoder["urls"])
+	result["config"] = flattenStringMap(decoder["config"])
+	result["headers"] = flattenStringMap(decoder["headers"])
+
+	// Druid reports an unbounded cache as Integer.MAX_VALUE.
+	result["capacity"] = 0
+	if capacity := flattenInt(decoder["capacity"]); capacity != math.MaxInt32 {
+		result["capacity"] = capacity
+	}
+}
+
+// inputFormatOptions lists the input_format attributes that only apply to a
+// single input format type.
+var inputFormatOptions = map[string]string{
+	"avro_bytes_decoder":     "avro_stream",
+	"binary_as_string":       "avro_stream",
+	"extract_unions_by_type": "avro_stream",
+	"proto_bytes_decoder":    "protobuf",
+	"csv":                    "csv",
+	"tsv":                    "tsv",
+	"regex":                  "regex",
+	"value_format":           "kafka",
+	"key_format":             "kafka",
+	"header_format":          "kafka",
+	"header_column_prefix":   "kafka",
+	"key_column_name":        "kafka",
+	"timestamp_column_name":  "kafka",
+	"topic_column_name":      "kafka",
+}
+
+// validateInputFormat checks that the input format sets the options its type
+// requires and no options of other types.
+func validateInputFormat(inputFormat map[string]interface{}, path string) error {
+	formatType, _ := inputFormat["type"].(string)
+
+	for _, key := range sortedKeys(inputFormatOptions) {
+		if format := inputFormatOptions[key]; format != formatType && isSet(inputFormat[key]) {
+			return fmt.Errorf("%s: %s is only supported by the %s input format", path, key, format)
+		}
+	}
+
+	switch formatType {
+	case "avro_stream":
+		decoders, _ := inputFormat["avro_bytes_decoder"].([]interface{})
+		if len(decoders) == 0 || decoders[0] == nil {
+			return fmt.Errorf("%s: avro_stream input format requires avro_bytes_decoder", path)
+		}
+		if err := validateAvroBytesDecoder(decoders[0].(map[string]interface{}), path+".avro_bytes_decoder.0"); err != nil {
+			return err
+		}
+	case "kafka":
+		valueFormat := firstBlock(inputFormat["value_format"])
+		if valueFormat == nil {
+			return fmt.Errorf("%s: kafka input format requires value_format", path)
+		}
+		for _, key := range []string{"value_format", "key_format"} {
+			nested := firstBlock(inputFormat[key])
+			if nested == nil {
+				continue
+			}
+			if nested["type"] == "kafka" {
+				return fmt.Errorf("%s.%s.0: kafka input formats cannot be nested", path, key)
+			}
+			if err := validateInputFormat(nested, path+"."+key+".0"); err != nil {
+				return err
+			}
+		}
+	case "csv", "tsv":
+		options := firstBlock(inputFormat[formatType])
+		if options == nil {
+			return fmt.Errorf("%s: %s input format requires a %s block", path, formatType, formatType)
+		}
+		columns, _ := options["columns"].([]interface{})
+		findColumns, _ := options["find_columns_from_header"].(bool)
+		if len(columns) == 0 && !findColumns {
+			return fmt.Errorf("%s.%s.0: columns is required unless find_columns_from_header is set", path, formatType)
+		}
+		if len(columns) > 0 && findColumns {
+			return fmt.Errorf("%s.%s.0: columns cannot be set together with find_columns_from_header", path, formatType)
+		}
+	case "regex":
+		if firstBlock(inputFormat["regex"]) == nil {
+			return fmt.Errorf("%s: regex input format requires a regex block", path)
+		}
+	case "protobuf":
+		decoders, _ := inputFormat["proto_bytes_decoder"].([]interface{})
+		if len(decoders) == 0 || decoders[0] == nil {
+			return fmt.Errorf("%s: protobuf input format requires proto_bytes_decoder", path)
+		}
+		if err := validateProtoBytesDecoder(decoders[0].(map[string]interface{}), path+".proto_bytes_decoder.0"); err != nil {
+			return err
+		}
+	}
+
+	if flattenSpecs, _ := inputFormat["flatten_spec"].([]interface{}); len(flattenSpecs) > 0 && flattenSpecs[0] != nil {
+		if err := validateFlattenSpec(flattenSpecs[0].(map[string]interface{}), path+".flatten_spec.0"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schemaRegistryDecoderOptions are the decoder attributes of the Confluent
+// Schema Registry decoders shared by the Avro and Protobuf formats.
+var schemaRegistryDecoderOptions = []string{"url", "urls", "capacity", "config", "headers"}
+
+// validateDecoderOptions checks that a decoder only sets the attributes its
+// type supports, given as a map from decoder type to attributes.
+func validateDecoderOptions(decoder map[string]interface{}, allowed map[string][]string, path string) error {
+	decoderType, _ := decoder["type"].(string)
+
+	used := map[string]bool{"type": true}
+	for _, key := range allowed[decoderType] {
+		used[key] = true
+	}
+	for _, key := range sortedKeys(decoder) {
+		if !used[key] && isSet(decoder[key]) {
+			return fmt.Errorf("%s: %s is not supported by the %s decoder", path, key, decoderType)
+		}
+	}
+
+	if decoderType == "schema_registry" {
+		url, _ := decoder["url"].(string)
+		urls, _ := decoder["urls"].([]interface{})
+		if (url == "") == (len(urls) == 0) {
+			return fmt.Errorf("%s: schema_registry decoder requires exactly one of url or urls", path)
+		}
+	}
+
+	return nil
+}
+
+func validateAvroBytesDecoder(decoder map[string]interface{}, path string) error {
+	decoderType, _ := decoder["type"].(string)
+	url, _ := decoder["url"].(string)
+	topic, _ := decoder["topic"].(string)
+	avroSchema, _ := decoder["schema"].(string)
+
+	allowed := map[string][]string{
+		"schema_registry": schemaRegistryDecoderOptions,
+		"schema_repo":     {"url", "topic"},
+		"schema_inline":   {"schema"},
+	}
+	if err := validateDecoderOptions(decoder, allowed, path); err != nil {
+		return err
+	}
+
+	switch decoderType {
+	case "schema_repo":
+		if url == "" || topic == "" {
+			return fmt.Errorf("%s: schema_repo decoder requires url and topic", path)
+		}
+	case "schema_inline":
+		if avroSchema == "" {
+			return fmt.Errorf("%s: schema_inline decoder requires schema", path)
+		}
+	}
+
+	return nil
+}
+
+func validateProtoBytesDecoder(decoder map[string]interface{}, path string) error {
+	decoderType, _ := decoder["type"].(string)
+	descriptor, _ := decoder["descriptor"].(string)
+
+	allowed := map[string][]string{
+		"schema_registry": schemaRegistryDecoderOptions,
+		"file":            {"descriptor", "proto_message_type"},
+	}
+	if err := validateDecoderOptions(decoder, allowed, path); err != nil {
+		return err
+	}
+
+	if decoderType == "file" && descriptor == "" {
+		return fmt.Errorf("%s: file decoder requires descriptor", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,784 @@
+package provider
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testInputFormat decodes an input_format block through the schema, so that
+// defaults are filled in the way Terraform would.
+func testInputFormat(t *testing.T, inputFormat map[string]interface{}) map[string]interface{} {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"input_format": []interface{}{inputFormat},
+	})
+	return d.Get("input_format.0").(map[string]interface{})
+}
+
+func TestBuildIOConfigAvroStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "schema registry decoder",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":     "schema_registry",
+						"url":      "http://schema-registry:8081",
+						"capacity": 100,
+						"config": map[string]interface{}{
+							"basic.auth.credentials.source": "USER_INFO",
+							"basic.auth.user.info":          "user:password",
+						},
+						"headers": map[string]interface{}{
+							"X-Tenant": "analytics",
+						},
+					},
+				},
+				"binary_as_string":       true,
+				"extract_unions_by_type": true,
+			},
+			expected: map[string]interface{}{
+				"type": "avro_stream",
+				"avroBytesDecoder": map[string]interface{}{
+					"type":     "schema_registry",
+					"url":      "http://schema-registry:8081",
+					"capacity": 100,
+					"config": map[string]interface{}{
+						"basic.auth.credentials.source": "USER_INFO",
+						"basic.auth.user.info":          "user:password",
+					},
+					"headers": map[string]interface{}{
+						"X-Tenant": "analytics",
+					},
+				},
+				"binaryAsString":      true,
+				"extractUnionsByType": true,
+			},
+		},
+		{
+			name: "schema registry decoder with several urls",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_registry",
+						"urls": []interface{}{"http://registry-1:8081", "http://registry-2:8081"},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "avro_stream",
+				"avroBytesDecoder": map[string]interface{}{
+					"type": "schema_registry",
+					"urls": []interface{}{"http://registry-1:8081", "http://registry-2:8081"},
+				},
+			},
+		},
+		{
+			name: "schema repo decoder",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":  "schema_repo",
+						"url":   "http://schema-repo:8081",
+						"topic": "events",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "avro_stream",
+				"avroBytesDecoder": map[string]interface{}{
+					"type": "schema_repo",
+					"subjectAndIdConverter": map[string]interface{}{
+						"type":  "avro_1124",
+						"topic": "events",
+					},
+					"schemaRepository": map[string]interface{}{
+						"type": "avro_1124_rest_client",
+						"url":  "http://schema-repo:8081",
+					},
+				},
+			},
+		},
+		{
+			name: "inline schema decoder",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":   "schema_inline",
+						"schema": `{"type": "record", "name": "Event", "fields": [{"name": "id", "type": "string"}]}`,
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "avro_stream",
+				"avroBytesDecoder": map[string]interface{}{
+					"type": "schema_inline",
+					"schema": map[string]interface{}{
+						"type": "record",
+						"name": "Event",
+						"fields": []interface{}{
+							map[string]interface{}{"name": "id", "type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"input_format": []interface{}{tt.input},
+			})
+			result := buildIOConfig(d)
+			assert.Equal(t, tt.expected, result["inputFormat"])
+		})
+	}
+}
+
+func TestBuildIOConfigProtobuf(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "file decoder",
+			input: map[string]interface{}{
+				"type": "protobuf",
+				"proto_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":               "file",
+						"descriptor":         "file:///opt/druid/proto/metrics.desc",
+						"proto_message_type": "com.example.Metrics",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "protobuf",
+				"protoBytesDecoder": map[string]interface{}{
+					"type":             "file",
+					"descriptor":       "file:///opt/druid/proto/metrics.desc",
+					"protoMessageType": "com.example.Metrics",
+				},
+			},
+		},
+		{
+			name: "schema registry decoder",
+			input: map[string]interface{}{
+				"type": "protobuf",
+				"proto_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_registry",
+						"url":  "http://schema-registry:8081",
+						"config": map[string]interface{}{
+							"basic.auth.credentials.source": "USER_INFO",
+						},
+						"headers": map[string]interface{}{
+							"X-Tenant": "analytics",
+						},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type": "protobuf",
+				"protoBytesDecoder": map[string]interface{}{
+					"type": "schema_registry",
+					"url":  "http://schema-registry:8081",
+					"config": map[string]interface{}{
+						"basic.auth.credentials.source": "USER_INFO",
+					},
+					"headers": map[string]interface{}{
+						"X-Tenant": "analytics",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"input_format": []interface{}{tt.input},
+			})
+			result := buildIOConfig(d)
+			assert.Equal(t, tt.expected, result["inputFormat"])
+		})
+	}
+}
+
+func TestBuildIOConfigDelimited(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "csv with columns",
+			input: map[string]interface{}{
+				"type": "csv",
+				"csv": []interface{}{
+					map[string]interface{}{
+						"columns":        []interface{}{"timestamp", "page", "tags"},
+						"list_delimiter": "|",
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type":          "csv",
+				"columns":       []interface{}{"timestamp", "page", "tags"},
+				"listDelimiter": "|",
+			},
+		},
+		{
+			name: "tsv with header",
+			input: map[string]interface{}{
+				"type": "tsv",
+				"tsv": []interface{}{
+					map[string]interface{}{
+						"delimiter":                "|",
+						"find_columns_from_header": true,
+						"skip_header_rows":         2,
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type":                  "tsv",
+				"delimiter":             "|",
+				"findColumnsFromHeader": true,
+				"skipHeaderRows":        2,
+			},
+		},
+		{
+			name: "regex",
+			input: map[string]interface{}{
+				"type": "regex",
+				"regex": []interface{}{
+					map[string]interface{}{
+						"pattern": `^(\S+) (\S+)$`,
+						"columns": []interface{}{"timestamp", "message"},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"type":    "regex",
+				"pattern": `^(\S+) (\S+)$`,
+				"columns": []interface{}{"timestamp", "message"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"input_format": []interface{}{tt.input},
+			})
+			result := buildIOConfig(d)
+			assert.Equal(t, tt.expected, result["inputFormat"])
+		})
+	}
+}
+
+func TestDelimitedFormatConflicts(t *testing.T) {
+	config := func(inputFormat map[string]interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"datasource": "test-datasource",
+			"timestamp_spec": []interface{}{
+				map[string]interface{}{"column": "__time"},
+			},
+			"topic":               "test-topic",
+			"input_format":        []interface{}{inputFormat},
+			"consumer_properties": map[string]interface{}{"bootstrap.servers": "localhost:9092"},
+		})
+	}
+
+	diags := resourceKafkaSupervisor().Validate(config(map[string]interface{}{
+		"type": "csv",
+		"csv":  []interface{}{map[string]interface{}{"columns": []interface{}{"page"}}},
+		"tsv":  []interface{}{map[string]interface{}{"columns": []interface{}{"page"}}},
+	}))
+	assert.True(t, diags.HasError())
+
+	diags = resourceKafkaSupervisor().Validate(config(map[string]interface{}{
+		"type": "kafka",
+		"value_format": []interface{}{
+			map[string]interface{}{
+				"type":  "regex",
+				"regex": []interface{}{map[string]interface{}{"pattern": "(.*)"}},
+				"csv":   []interface{}{map[string]interface{}{"columns": []interface{}{"page"}}},
+			},
+		},
+	}))
+	assert.True(t, diags.HasError())
+
+	diags = resourceKafkaSupervisor().Validate(config(map[string]interface{}{
+		"type": "csv",
+		"csv":  []interface{}{map[string]interface{}{"columns": []interface{}{"page"}}},
+	}))
+	assert.False(t, diags.HasError())
+}
+
+func TestFlattenInputFormatDelimited(t *testing.T) {
+	// A tsv input format as returned by Druid, with defaults filled in
+	druidFormat := map[string]interface{}{
+		"type":                  "tsv",
+		"columns":               []interface{}{"timestamp", "page"},
+		"listDelimiter":         nil,
+		"delimiter":             "\t",
+		"findColumnsFromHeader": false,
+		"skipHeaderRows":        float64(0),
+	}
+
+	inputFormat := flattenInputFormat(druidFormat, nil, true)[0].(map[string]interface{})
+	assert.Empty(t, inputFormat["csv"])
+	assert.Empty(t, inputFormat["regex"])
+	require.Len(t, inputFormat["tsv"], 1)
+	tsv := inputFormat["tsv"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"timestamp", "page"}, tsv["columns"])
+	assert.Equal(t, "", tsv["delimiter"])
+	assert.Equal(t, false, tsv["find_columns_from_header"])
+	assert.Equal(t, 0, tsv["skip_header_rows"])
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("input_format", []interface{}{inputFormat}))
+}
+
+func TestBuildIOConfigKafka(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"input_format": []interface{}{
+			map[string]interface{}{
+				"type": "kafka",
+				"value_format": []interface{}{
+					map[string]interface{}{
+						"type": "json",
+					},
+				},
+				"key_format": []interface{}{
+					map[string]interface{}{
+						"type": "csv",
+						"csv": []interface{}{
+							map[string]interface{}{
+								"columns": []interface{}{"tenant"},
+							},
+						},
+					},
+				},
+				"header_format": []interface{}{
+					map[string]interface{}{},
+				},
+				"header_column_prefix":  "header.",
+				"timestamp_column_name": "kafka_time",
+			},
+		},
+	})
+
+	result := buildIOConfig(d)
+	assert.Equal(t, map[string]interface{}{
+		"type": "kafka",
+		"valueFormat": map[string]interface{}{
+			"type": "json",
+		},
+		"keyFormat": map[string]interface{}{
+			"type":    "csv",
+			"columns": []interface{}{"tenant"},
+		},
+		"headerFormat": map[string]interface{}{
+			"type": "string",
+		},
+		"headerColumnPrefix":  "header.",
+		"timestampColumnName": "kafka_time",
+	}, result["inputFormat"])
+}
+
+func TestFlattenInputFormatKafka(t *testing.T) {
+	// A kafka input format as returned by Druid, with defaults filled in
+	druidFormat := map[string]interface{}{
+		"type": "kafka",
+		"valueFormat": map[string]interface{}{
+			"type": "json",
+			"flattenSpec": map[string]interface{}{
+				"useFieldDiscovery": true,
+				"fields":            []interface{}{},
+			},
+		},
+		"headerFormat": map[string]interface{}{
+			"type":     "string",
+			"encoding": "UTF-8",
+		},
+		"keyFormat":           nil,
+		"headerColumnPrefix":  "header.",
+		"keyColumnName":       "kafka.key",
+		"timestampColumnName": "kafka.timestamp",
+		"topicColumnName":     "kafka.topic",
+	}
+
+	result := flattenInputFormat(druidFormat, nil, true)
+	require.Len(t, result, 1)
+	inputFormat := result[0].(map[string]interface{})
+	assert.Equal(t, "kafka", inputFormat["type"])
+	assert.Equal(t, "header.", inputFormat["header_column_prefix"])
+	assert.Equal(t, "", inputFormat["key_column_name"])
+	assert.Equal(t, "", inputFormat["timestamp_column_name"])
+	assert.Equal(t, "", inputFormat["topic_column_name"])
+	assert.Empty(t, inputFormat["key_format"])
+
+	valueFormat := inputFormat["value_format"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "json", valueFormat["type"])
+	assert.Empty(t, valueFormat["flatten_spec"])
+	assert.NotContains(t, valueFormat, "value_format")
+
+	headerFormat := inputFormat["header_format"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "string", headerFormat["type"])
+	assert.Equal(t, "", headerFormat["encoding"])
+
+	// Defaults that are set in the configuration are kept
+	prior := testInputFormat(t, map[string]interface{}{
+		"type":            "kafka",
+		"key_column_name": "kafka.key",
+	})
+	inputFormat = flattenInputFormat(druidFormat, prior, true)[0].(map[string]interface{})
+	assert.Equal(t, "kafka.key", inputFormat["key_column_name"])
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("input_format", result))
+}
+
+func TestValidateInputFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "json without options",
+			input: map[string]interface{}{
+				"type": "json",
+			},
+		},
+		{
+			name: "avro stream without decoder",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+			},
+			expectedError: "input_format.0: avro_stream input format requires avro_bytes_decoder",
+		},
+		{
+			name: "avro options on json",
+			input: map[string]interface{}{
+				"type":             "json",
+				"binary_as_string": true,
+			},
+			expectedError: "input_format.0: binary_as_string is only supported by the avro_stream input format",
+		},
+		{
+			name: "schema registry without url",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_registry",
+					},
+				},
+			},
+			expectedError: "input_format.0.avro_bytes_decoder.0: schema_registry decoder requires exactly one of url or urls",
+		},
+		{
+			name: "schema registry with url and urls",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_registry",
+						"url":  "http://registry-1:8081",
+						"urls": []interface{}{"http://registry-2:8081"},
+					},
+				},
+			},
+			expectedError: "input_format.0.avro_bytes_decoder.0: schema_registry decoder requires exactly one of url or urls",
+		},
+		{
+			name: "schema repo without topic",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_repo",
+						"url":  "http://schema-repo:8081",
+					},
+				},
+			},
+			expectedError: "input_format.0.avro_bytes_decoder.0: schema_repo decoder requires url and topic",
+		},
+		{
+			name: "inline schema with registry options",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":     "schema_inline",
+						"schema":   `{"type": "string"}`,
+						"capacity": 10,
+					},
+				},
+			},
+			expectedError: "input_format.0.avro_bytes_decoder.0: capacity is not supported by the schema_inline decoder",
+		},
+		{
+			name: "inline schema without schema",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_inline",
+					},
+				},
+			},
+			expectedError: "input_format.0.avro_bytes_decoder.0: schema_inline decoder requires schema",
+		},
+		{
+			name: "protobuf without decoder",
+			input: map[string]interface{}{
+				"type": "protobuf",
+			},
+			expectedError: "input_format.0: protobuf input format requires proto_bytes_decoder",
+		},
+		{
+			name: "protobuf decoder on avro stream",
+			input: map[string]interface{}{
+				"type": "avro_stream",
+				"avro_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":   "schema_inline",
+						"schema": `{"type": "string"}`,
+					},
+				},
+				"proto_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":       "file",
+						"descriptor": "file:///metrics.desc",
+					},
+				},
+			},
+			expectedError: "input_format.0: proto_bytes_decoder is only supported by the protobuf input format",
+		},
+		{
+			name: "file decoder without descriptor",
+			input: map[string]interface{}{
+				"type": "protobuf",
+				"proto_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":               "file",
+						"proto_message_type": "com.example.Metrics",
+					},
+				},
+			},
+			expectedError: "input_format.0.proto_bytes_decoder.0: file decoder requires descriptor",
+		},
+		{
+			name: "file decoder with registry url",
+			input: map[string]interface{}{
+				"type": "protobuf",
+				"proto_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type":       "file",
+						"descriptor": "file:///metrics.desc",
+						"url":        "http://schema-registry:8081",
+					},
+				},
+			},
+			expectedError: "input_format.0.proto_bytes_decoder.0: url is not supported by the file decoder",
+		},
+		{
+			name: "csv without options",
+			input: map[string]interface{}{
+				"type": "csv",
+			},
+			expectedError: "input_format.0: csv input format requires a csv block",
+		},
+		{
+			name: "csv without columns",
+			input: map[string]interface{}{
+				"type": "csv",
+				"csv": []interface{}{
+					map[string]interface{}{
+						"skip_header_rows": 1,
+					},
+				},
+			},
+			expectedError: "input_format.0.csv.0: columns is required unless find_columns_from_header is set",
+		},
+		{
+			name: "tsv with columns and header",
+			input: map[string]interface{}{
+				"type": "tsv",
+				"tsv": []interface{}{
+					map[string]interface{}{
+						"columns":                  []interface{}{"page"},
+						"find_columns_from_header": true,
+					},
+				},
+			},
+			expectedError: "input_format.0.tsv.0: columns cannot be set together with find_columns_from_header",
+		},
+		{
+			name: "csv options on tsv",
+			input: map[string]interface{}{
+				"type": "tsv",
+				"csv": []interface{}{
+					map[string]interface{}{
+						"columns": []interface{}{"page"},
+					},
+				},
+			},
+			expectedError: "input_format.0: csv is only supported by the csv input format",
+		},
+		{
+			name: "regex without options",
+			input: map[string]interface{}{
+				"type": "regex",
+			},
+			expectedError: "input_format.0: regex input format requires a regex block",
+		},
+		{
+			name: "kafka without value format",
+			input: map[string]interface{}{
+				"type": "kafka",
+			},
+			expectedError: "input_format.0: kafka input format requires value_format",
+		},
+		{
+			name: "kafka options on json",
+			input: map[string]interface{}{
+				"type":            "json",
+				"key_column_name": "key",
+			},
+			expectedError: "input_format.0: key_column_name is only supported by the kafka input format",
+		},
+		{
+			name: "nested kafka format",
+			input: map[string]interface{}{
+				"type": "kafka",
+				"value_format": []interface{}{
+					map[string]interface{}{
+						"type": "kafka",
+					},
+				},
+			},
+			expectedError: "input_format.0.value_format.0: kafka input formats cannot be nested",
+		},
+		{
+			name: "invalid kafka value format",
+			input: map[string]interface{}{
+				"type": "kafka",
+				"value_format": []interface{}{
+					map[string]interface{}{
+						"type": "avro_stream",
+					},
+				},
+			},
+			expectedError: "input_format.0.value_format.0: avro_stream input format requires avro_bytes_decoder",
+		},
+		{
+			name: "valid kafka format",
+			input: map[string]interface{}{
+				"type": "kafka",
+				"value_format": []interface{}{
+					map[string]interface{}{
+						"type": "json",
+					},
+				},
+				"header_format": []interface{}{
+					map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name: "valid protobuf schema registry decoder",
+			input: map[string]interface{}{
+				"type": "protobuf",
+				"proto_bytes_decoder": []interface{}{
+					map[string]interface{}{
+						"type": "schema_registry",
+						"url":  "http://schema-registry:8081",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInputFormat(testInputFormat(t, tt.input), "input_format.0")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestFlattenAvroBytesDecoder(t *testing.T) {
+	// A decoder as returned by Druid, with unset options serialized as null
+	result := flattenAvroBytesDecoder(map[string]interface{}{
+		"type":     "schema_registry",
+		"url":      "http://schema-registry:8081",
+		"capacity": float64(math.MaxInt32),
+		"urls":     nil,
+		"config":   nil,
+		"headers":  map[string]interface{}{"X-Tenant": "analytics"},
+	})
+
+	require.Len(t, result, 1)
+	decoder := result[0].(map[string]interface{})
+	assert.Equal(t, "schema_registry", decoder["type"])
+	assert.Equal(t, "http://schema-registry:8081", decoder["url"])
+	assert.Equal(t, 0, decoder["capacity"])
+	assert.Empty(t, decoder["urls"])
+	assert.Equal(t, map[string]interface{}{"X-Tenant": "analytics"}, decoder["headers"])
+
+	result = flattenAvroBytesDecoder(map[string]interface{}{
+		"type": "schema_repo",
+		"subjectAndIdConverter": map[string]interface{}{
+			"type":  "avro_1124",
+			"topic": "events",
+		},
+		"schemaRepository": map[string]interface{}{
+			"type": "avro_1124_rest_client",
+			"url":  "http://schema-repo:8081",
+		},
+	})
+
+	decoder = result[0].(map[string]interface{})
+	assert.Equal(t, "events", decoder["topic"])
+	assert.Equal(t, "http://schema-repo:8081", decoder["url"])
+}
+
+func TestFlattenProtoBytesDecoder(t *testing.T) {
+	result := flattenProtoBytesDecoder(map[string]interface{}{
+		"type":             "file",
+		"descriptor":       "file:///metrics.desc",
+		"protoMessageType": "com.example.Metrics",
+	})
+
+	require.Len(t, result, 1)
+	decoder := result[0].(map[string]interface{})
+	assert.Equal(t, "file", decoder["type"])
+	assert.Equal(t, "file:///metrics.desc", decoder["descriptor"])
+	assert.Equal(t, "com.example.Metrics", decoder["proto_message_type"])
+	assert.Equal(t, "", decoder["url"])
+	assert.Equal(t, 0, decoder["capacity"])
+}
@@ -40,6 +40,9 @@ func New() *schema.Provider {
 		ResourcesMap: map[string]*schema.Resource{
 			"druid_kafka_supervisor": resourceKafkaSupervisor(),
 		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"druid_kafka_supervisor_history": dataSourceKafkaSupervisorHistory(),
+		},
 	}
 }
 
@@ -42,6 +42,10 @@ func TestProvider(t *testing.T) {
 	// Test that resources are registered
 	assert.Contains(t, provider.ResourcesMap, "druid_kafka_supervisor")
 	assert.NotNil(t, provider.ResourcesMap["druid_kafka_supervisor"])
+	
+	// Test that data sources are registered
+	assert.Contains(t, provider.DataSourcesMap, "druid_kafka_supervisor_history")
+	assert.NotNil(t, provider.DataSourcesMap["druid_kafka_supervisor_history"])
 }
 
 func TestProviderConfigure(t *testing.T) {
@@ -114,4 +118,9 @@ func TestProviderValidation(t *testing.T) {
 	resource := provider.ResourcesMap["druid_kafka_supervisor"]
 	err = resource.InternalValidate(nil, true)
 	assert.NoError(t, err)
+	
+	// Test data source validation
+	dataSource := provider.DataSourcesMap["druid_kafka_supervisor_history"]
+	err = dataSource.InternalValidate(nil, false)
+	assert.NoError(t, err)
 }
\ No newline at end of file
@@ -2,13 +2,24 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var dimensionTypes = []string{"string", "long", "float", "double", "json", "auto"}
+
 func resourceKafkaSupervisor() *schema.Resource {
 	return &schema.Resource{
 		Description: "Manages a Druid Kafka ingestion supervisor",
@@ -18,459 +29,798 @@ func resourceKafkaSupervisor() *schema.Resource {
 		UpdateContext: resourceKafkaSupervisorUpdate,
 		DeleteContext: resourceKafkaSupervisorDelete,
 
+		CustomizeDiff: resourceKafkaSupervisorCustomizeDiff,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceKafkaSupervisorV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceKafkaSupervisorStateUpgradeV0,
+			},
+		},
+
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceKafkaSupervisorImport,
 		},
 
-		Schema: map[string]*schema.Schema{
-			"datasource": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the Druid datasource",
-			},
-			
-			// Data Schema Configuration
-			"timestamp_spec": {
-				Type:        schema.TypeList,
-				Required:    true,
-				MaxItems:    1,
-				Description: "Timestamp specification",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"column": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Name of the timestamp column",
-						},
-						"format": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "auto",
-							Description: "Timestamp format (e.g., 'iso', 'millis', 'auto')",
-						},
-						"missing_value": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Default timestamp for rows with missing timestamps",
-						},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: resourceKafkaSupervisorSchema(),
+	}
+}
+
+// resourceKafkaSupervisorSchema returns the attributes of the supervisor
+// resource, shared with the schema of earlier state versions.
+func resourceKafkaSupervisorSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"datasource": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name of the Druid datasource. Changing this replaces the supervisor",
+		},
+		
+		"supervisor_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "The supervisor ID. Defaults to the datasource name; set it to run several Kafka supervisors feeding the same datasource (requires a Druid version that supports supervisor IDs). Changing this replaces the supervisor",
+		},
+		
+		// Data Schema Configuration
+		"timestamp_spec": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Timestamp specification",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"column": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Name of the timestamp column",
+					},
+					"format": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "auto",
+						Description: "Timestamp format (e.g., 'iso', 'millis', 'auto')",
+					},
+					"missing_value": {
+						Type:             schema.TypeString,
+						Optional:         true,
+						Description:      "ISO 8601 timestamp used for rows with missing timestamps",
+						ValidateFunc:     validateISO8601DateTime,
+						DiffSuppressFunc: suppressEquivalentDateTimes,
 					},
 				},
 			},
-			
-			"dimensions_spec": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				MaxItems:    1,
-				Description: "Dimension specification",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"dimensions": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Description: "List of dimension specifications",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"name": {
-										Type:        schema.TypeString,
-										Required:    true,
-										Description: "Dimension name",
-									},
-									"type": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Default:     "string",
-										Description: "Dimension type (string, long, float, double)",
-									},
-									"multi_value_handling": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Description: "How to handle multi-value dimensions",
-									},
+		},
+		
+		"dimensions_spec": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Dimension specification",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"dimensions": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "List of dimension specifications",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "Dimension name",
+								},
+								"type": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Default:      "string",
+									ValidateFunc: validation.StringInSlice(dimensionTypes, false),
+									Description:  "Dimension type (string, long, float, double, json, auto)",
+								},
+								"multi_value_handling": {
+									Type:             schema.TypeString,
+									Optional:         true,
+									ValidateFunc:     validation.StringInSlice([]string{"SORTED_ARRAY", "SORTED_SET", "ARRAY"}, true),
+									Description:      "How to handle multi-value string dimensions (SORTED_ARRAY, SORTED_SET, ARRAY)",
+									DiffSuppressFunc: suppressCaseDifferences,
+								},
+								"create_bitmap_index": {
+									Type:        schema.TypeBool,
+									Optional:    true,
+									Default:     true,
+									Description: "Whether a bitmap index is built for the string dimension",
 								},
 							},
 						},
-						"dimension_exclusions": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Description: "List of columns to exclude from dimensions",
-							Elem:        &schema.Schema{Type: schema.TypeString},
-						},
-						"spatial_dimensions": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Description: "Spatial dimension configurations",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"dim_name": {
-										Type:        schema.TypeString,
-										Required:    true,
-										Description: "Name of the spatial dimension",
-									},
-									"dims": {
-										Type:        schema.TypeList,
-										Required:    true,
-										Description: "List of coordinate columns",
-										Elem:        &schema.Schema{Type: schema.TypeString},
-									},
+					},
+					"use_schema_discovery": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Whether dimensions not listed in dimensions are discovered from the input with their types, as auto dimensions",
+					},
+					"include_all_dimensions": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Whether discovered dimensions are ingested in addition to the listed dimensions",
+					},
+					"dimension_exclusions": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "List of columns to exclude from dimensions",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"spatial_dimensions": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Spatial dimension configurations",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"dim_name": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "Name of the spatial dimension",
+								},
+								"dims": {
+									Type:        schema.TypeList,
+									Required:    true,
+									Description: "List of coordinate columns",
+									Elem:        &schema.Schema{Type: schema.TypeString},
 								},
 							},
 						},
 					},
 				},
 			},
-			
-			"metrics_spec": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				Description: "List of aggregation metrics",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Metric name",
-						},
-						"type": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Aggregation type (count, longSum, doubleSum, etc.)",
-						},
-						"field_name": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Input field name for the metric",
-						},
+		},
+		
+		"metrics_spec": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "List of aggregation metrics",
+			Elem:        aggregatorSchema(false),
+		},
+		
+		"granularity_spec": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Granularity specification",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "uniform",
+						ValidateFunc: validation.StringInSlice([]string{"uniform", "arbitrary"}, false),
+						Description:  "Granularity type (uniform or arbitrary)",
 					},
-				},
-			},
-			
-			"granularity_spec": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				MaxItems:    1,
-				Description: "Granularity specification",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"type": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "uniform",
-							Description: "Granularity type (uniform or arbitrary)",
-						},
-						"segment_granularity": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "HOUR",
-							Description: "Segment granularity (HOUR, DAY, WEEK, etc.)",
-						},
-						"query_granularity": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "NONE",
-							Description: "Query granularity (NONE, SECOND, MINUTE, etc.)",
-						},
-						"rollup": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-							Description: "Whether to enable rollup",
+					"segment_granularity":        namedGranularitySchema("HOUR", "Segment granularity (HOUR, DAY, WEEK, etc.)"),
+					"segment_granularity_period": periodGranularityAttribute("segment_granularity"),
+					"query_granularity":          namedGranularitySchema("NONE", "Query granularity (NONE, SECOND, MINUTE, etc.)"),
+					"query_granularity_period":   periodGranularityAttribute("query_granularity"),
+					"intervals": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "ISO 8601 intervals of the data to ingest, required by the arbitrary type",
+						Elem: &schema.Schema{
+							Type:             schema.TypeString,
+							ValidateFunc:     validateISO8601Interval,
+							DiffSuppressFunc: suppressEquivalentIntervals,
 						},
 					},
+					"rollup": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Whether to enable rollup",
+					},
 				},
 			},
-			
-			// IO Configuration
-			"topic": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ExactlyOneOf: []string{"topic", "topic_pattern"},
-				Description:  "Kafka topic to consume from",
-			},
-			
-			"topic_pattern": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ExactlyOneOf: []string{"topic", "topic_pattern"},
-				Description:  "Regex pattern for multiple Kafka topics",
-			},
-			
-			"input_format": {
-				Type:        schema.TypeList,
-				Required:    true,
-				MaxItems:    1,
-				Description: "Input format configuration",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"type": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Input format type (json, csv, tsv, etc.)",
-						},
-						"flat_spec": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							MaxItems:    1,
-							Description: "Flat spec for delimited formats",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"use_field_discovery": {
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Default:     true,
-										Description: "Whether to use field discovery",
-									},
-									"delimiter": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Description: "Field delimiter",
-									},
-									"columns": {
-										Type:        schema.TypeList,
-										Optional:    true,
-										Description: "Column names",
-										Elem:        &schema.Schema{Type: schema.TypeString},
-									},
+		},
+		
+		"transform_spec": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Transforms and filter applied to input rows at ingestion time",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"transforms": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Columns derived from expressions",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"type": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Default:      "expression",
+									ValidateFunc: validation.StringInSlice([]string{"expression"}, false),
+									Description:  "Transform type",
+								},
+								"name": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "Name of the output column",
+								},
+								"expression": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "Druid expression computing the column",
 								},
 							},
 						},
 					},
+					"filter": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "Filter selecting the rows to ingest",
+						Elem:        filterSchema(maxFilterDepth),
+					},
 				},
 			},
-			
-			"consumer_properties": {
-				Type:        schema.TypeMap,
-				Required:    true,
-				Description: "Kafka consumer properties",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				ValidateFunc: func(v interface{}, k string) (warnings []string, errors []error) {
-					props := v.(map[string]interface{})
-					if _, ok := props["bootstrap.servers"]; !ok {
-						errors = append(errors, fmt.Errorf("bootstrap.servers is required in consumer_properties"))
-					}
-					return warnings, errors
-				},
-			},
-			
-			"task_count": {
-				Type:         schema.TypeInt,
-				Optional:     true,
-				Default:      1,
-				Description:  "Number of reading tasks",
-				ValidateFunc: validation.IntAtLeast(1),
-			},
-			
-			"replicas": {
-				Type:         schema.TypeInt,
-				Optional:     true,
-				Default:      1,
-				Description:  "Number of replica tasks",
-				ValidateFunc: validation.IntAtLeast(1),
-			},
-			
-			"task_duration": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "PT1H",
-				Description: "Task duration in ISO 8601 format",
-			},
-			
-			"use_earliest_offset": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Whether to use earliest offset for new topics",
-			},
-			
-			"completion_timeout": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "PT30M",
-				Description: "Task completion timeout in ISO 8601 format",
+		},
+		
+		// IO Configuration
+		"topic": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: []string{"topic", "topic_pattern"},
+			Description:  "Kafka topic to consume from",
+		},
+		
+		"topic_pattern": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: []string{"topic", "topic_pattern"},
+			ValidateFunc: validateTopicPattern,
+			Description:  "Java regular expression matching the Kafka topics to consume from. Use the kafka input format's topic_column_name to record each row's topic",
+		},
+		
+		"input_format": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Input format configuration",
+			Elem:        inputFormatSchema("input_format.0", true),
+		},
+		
+		"consumer_properties": {
+			Type:        schema.TypeMap,
+			Required:    true,
+			Description: "Kafka consumer properties",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			ValidateFunc: func(v interface{}, k string) (warnings []string, errors []error) {
+				props := v.(map[string]interface{})
+				if _, ok := props["bootstrap.servers"]; !ok {
+					errors = append(errors, fmt.Errorf("bootstrap.servers is required in consumer_properties"))
+				}
+				return warnings, errors
 			},
-			
-			"idle_config": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				MaxItems:    1,
-				Description: "Idle configuration for the supervisor",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"enabled": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Whether idle detection is enabled",
-						},
-						"inactive_after_millis": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Milliseconds of inactivity before going idle",
-						},
+		},
+		
+		"task_count": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          1,
+			Description:      "Number of reading tasks. Changes are ignored while the task autoscaler is enabled",
+			ValidateFunc:     validation.IntAtLeast(1),
+			DiffSuppressFunc: suppressAutoScaledTaskCount,
+		},
+		
+		"replicas": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      1,
+			Description:  "Number of replica tasks",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		
+		"task_duration": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "PT1H",
+			Description:      "Task duration in ISO 8601 format",
+			DiffSuppressFunc: suppressEquivalentDurations,
+		},
+		
+		"use_earliest_offset": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether to use earliest offset for new topics",
+		},
+		
+		"completion_timeout": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "PT30M",
+			Description:      "Task completion timeout in ISO 8601 format",
+			DiffSuppressFunc: suppressEquivalentDurations,
+		},
+		
+		"start_delay": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "Delay before the supervisor starts managing tasks in ISO 8601 format. Druid defaults to PT5S",
+			ValidateFunc:     validateISO8601Duration,
+			DiffSuppressFunc: suppressEquivalentDurations,
+		},
+		
+		"period": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "How often the supervisor runs its management logic in ISO 8601 format. Druid defaults to PT30S",
+			ValidateFunc:     validateISO8601Duration,
+			DiffSuppressFunc: suppressEquivalentDurations,
+		},
+		
+		"poll_timeout": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Milliseconds the Kafka consumer waits for records per poll. Druid defaults to 100",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		
+		"late_message_rejection_period": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "Reject messages with timestamps earlier than this ISO 8601 period before the task started",
+			ValidateFunc:     validateISO8601Duration,
+			DiffSuppressFunc: suppressEquivalentDurations,
+			ConflictsWith:    []string{"late_message_rejection_start_date_time"},
+		},
+		
+		"late_message_rejection_start_date_time": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "Reject messages with timestamps earlier than this ISO 8601 date time",
+			ValidateFunc:     validateISO8601DateTime,
+			DiffSuppressFunc: suppressEquivalentDateTimes,
+			ConflictsWith:    []string{"late_message_rejection_period"},
+		},
+		
+		"early_message_rejection_period": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "Reject messages with timestamps later than this ISO 8601 period after the task ends",
+			ValidateFunc:     validateISO8601Duration,
+			DiffSuppressFunc: suppressEquivalentDurations,
+		},
+		
+		"autoscaler_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Lag-based task autoscaler configuration",
+			Elem:        autoScalerConfigSchema(),
+		},
+		
+		"idle_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Idle configuration for the supervisor",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"enabled": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Whether idle detection is enabled",
+					},
+					"inactive_after_millis": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Milliseconds of inactivity before going idle",
 					},
 				},
 			},
-			
-			// Tuning Configuration
-			"tuning_config": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				MaxItems:    1,
-				Description: "Tuning configuration",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"max_rows_per_segment": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     5000000,
-							Description: "Maximum number of rows per segment",
-						},
-						"max_rows_in_memory": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     150000,
-							Description: "Maximum rows in memory before persisting",
-						},
-						"max_bytes_in_memory": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Maximum bytes in memory before persisting",
-						},
-						"skip_bytes_in_memory_overhead_check": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Skip overhead check for bytes in memory",
-						},
-						"max_pending_persists": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Maximum pending persist operations",
-						},
-						"index_spec": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							MaxItems:    1,
-							Description: "Index specification",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"bitmap": {
-										Type:        schema.TypeMap,
-										Optional:    true,
-										Description: "Bitmap index configuration",
-										Elem:        &schema.Schema{Type: schema.TypeString},
-									},
-									"dimension_compression": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Default:     "lz4",
-										Description: "Dimension compression algorithm",
-									},
-									"metric_compression": {
-										Type:        schema.TypeString,
-										Optional:     true,
-										Default:     "lz4",
-										Description: "Metric compression algorithm",
-									},
+		},
+		
+		// Tuning Configuration
+		"tuning_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Tuning configuration",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"max_rows_per_segment": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Maximum number of rows per segment. Druid defaults to 5000000",
+					},
+					"max_rows_in_memory": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Maximum rows in memory before persisting. Druid defaults to 150000",
+					},
+					"max_bytes_in_memory": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Maximum bytes in memory before persisting",
+					},
+					"skip_bytes_in_memory_overhead_check": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Skip overhead check for bytes in memory",
+					},
+					"max_pending_persists": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Maximum pending persist operations",
+					},
+					"index_spec": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "Index specification of published segments",
+						Elem:        indexSpecSchema(),
+					},
+					"index_spec_for_intermediate_persists": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "Index specification of intermediate persists, which are merged into published segments",
+						Elem:        indexSpecSchema(),
+					},
+					"appendable_index_spec": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "In-memory index rows are ingested into before they are persisted",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"type": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Default:      "onheap",
+									ValidateFunc: validation.StringInSlice([]string{"onheap", "offheap"}, false),
+									Description:  "Index type (onheap or offheap)",
+								},
+								"preserve_existing_metrics": {
+									Type:        schema.TypeBool,
+									Optional:    true,
+									Description: "Whether metric columns in the input are kept alongside their aggregates (onheap)",
 								},
 							},
 						},
-						"segment_write_out_medium_factory": {
-							Type:        schema.TypeMap,
-							Optional:    true,
-							Description: "Segment write-out medium factory configuration",
-							Elem:        &schema.Schema{Type: schema.TypeString},
-						},
-						"intermediate_persist_period": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "PT10M",
-							Description: "Intermediate persist period",
-						},
-						"max_parse_exceptions": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     2147483647,
-							Description: "Maximum parse exceptions allowed",
-						},
-						"max_saved_parse_exceptions": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Maximum saved parse exceptions",
-						},
-						"log_parse_exceptions": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Whether to log parse exceptions",
-						},
-						"reset_offset_automatically": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Automatically reset consumer offset on errors",
-						},
-						"worker_threads": {
-							Type:         schema.TypeInt,
-							Optional:     true,
-							Description:  "Number of worker threads",
-							ValidateFunc: validation.IntAtLeast(1),
-						},
-						"chat_threads": {
-							Type:         schema.TypeInt,
-							Optional:     true,
-							Description:  "Number of chat handler threads",
-							ValidateFunc: validation.IntAtLeast(1),
-						},
-						"chat_retries": {
-							Type:         schema.TypeInt,
-							Optional:     true,
-							Default:      8,
-							Description:  "Number of chat retries",
-							ValidateFunc: validation.IntAtLeast(0),
-						},
-						"http_timeout": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "PT10S",
-							Description: "HTTP timeout for task communication",
-						},
-						"shutdown_timeout": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "PT80S",
-							Description: "Task shutdown timeout",
+					},
+					"partitions_spec": {
+						Type:          schema.TypeList,
+						Optional:      true,
+						MaxItems:      1,
+						Description:   "Dynamic partitioning of published segments, used instead of max_rows_per_segment and max_total_rows",
+						ConflictsWith: []string{"tuning_config.0.max_rows_per_segment", "tuning_config.0.max_total_rows"},
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"type": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Default:      "dynamic",
+									ValidateFunc: validation.StringInSlice([]string{"dynamic"}, false),
+									Description:  "Partitions spec type. Streaming ingestion only supports dynamic",
+								},
+								"max_rows_per_segment": {
+									Type:         schema.TypeInt,
+									Optional:     true,
+									ValidateFunc: validation.IntAtLeast(1),
+									Description:  "Maximum number of rows per segment",
+								},
+								"max_total_rows": {
+									Type:         schema.TypeInt,
+									Optional:     true,
+									ValidateFunc: validation.IntAtLeast(1),
+									Description:  "Maximum number of rows across all segments waiting to be published",
+								},
+							},
 						},
 					},
+					"max_total_rows": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						ValidateFunc: validation.IntAtLeast(1),
+						Description:  "Maximum number of rows across all segments waiting to be published",
+					},
+					"max_columns_to_merge": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						ValidateFunc: validation.IntAtLeast(-1),
+						Description:  "Maximum number of columns merged at once when publishing, -1 for no limit",
+					},
+					"segment_write_out_medium_factory": {
+						Type:        schema.TypeMap,
+						Optional:    true,
+						Description: "Segment write-out medium factory configuration",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"intermediate_persist_period": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Intermediate persist period. Druid defaults to PT10M",
+					},
+					"max_parse_exceptions": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Maximum parse exceptions allowed. Druid defaults to 2147483647",
+					},
+					"max_saved_parse_exceptions": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Maximum saved parse exceptions",
+					},
+					"log_parse_exceptions": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Whether to log parse exceptions",
+					},
+					"reset_offset_automatically": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Automatically reset consumer offset on errors",
+					},
+					"worker_threads": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Description:  "Number of worker threads",
+						ValidateFunc: validation.IntAtLeast(1),
+					},
+					"chat_threads": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Description:  "Number of chat handler threads",
+						ValidateFunc: validation.IntAtLeast(1),
+					},
+					"chat_retries": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Description:  "Number of chat retries. Druid defaults to 8",
+						ValidateFunc: validation.IntAtLeast(0),
+					},
+					"http_timeout": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "HTTP timeout for task communication. Druid defaults to PT10S",
+					},
+					"shutdown_timeout": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Task shutdown timeout. Druid defaults to PT80S",
+					},
+					"handoff_condition_timeout": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						ValidateFunc: validation.IntAtLeast(0),
+						Description:  "Milliseconds to wait for segment handoff, 0 to wait forever. Druid defaults to 900000",
+					},
+					"offset_fetch_period": {
+						Type:             schema.TypeString,
+						Optional:         true,
+						ValidateFunc:     validateISO8601Duration,
+						DiffSuppressFunc: suppressEquivalentDurations,
+						Description:      "How often the supervisor fetches the latest Kafka offsets to compute lag. Druid defaults to PT30S",
+					},
+					"max_records_per_poll": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						ValidateFunc: validation.IntAtLeast(1),
+						Description:  "Maximum number of records a task fetches per poll",
+					},
+					"report_parse_exceptions": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Fail on the first parse exception. Overrides max_parse_exceptions to 0 and max_saved_parse_exceptions to at most 1",
+					},
 				},
 			},
-			
-			"context": {
-				Type:        schema.TypeMap,
-				Optional:    true,
-				Description: "Additional context parameters",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			
-			"suspended": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Whether the supervisor is suspended",
+		},
+		
+		"context": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Additional context parameters",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		
+		"suspended": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether the supervisor is suspended. Changing only this value suspends or resumes the supervisor without resubmitting the spec",
+		},
+		
+		"spec_json": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "Raw supervisor spec JSON deep-merged over the spec generated from the typed attributes, for options the typed schema does not cover. Objects are merged recursively, other values replace the generated ones and null removes a generated key. Overridden keys are not read back into the typed attributes",
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+		
+		"spec_version": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Pin the supervisor to a previous spec version from its history (see the druid_kafka_supervisor_history data source). While set, that version's spec is submitted instead of the one generated from the configuration, and changes made outside Terraform resubmit it",
+		},
+		
+		"deletion_policy": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "terminate",
+			Description:  "What happens when the resource is destroyed: terminate the supervisor, suspend_only to leave it suspended for inspection, or terminate_and_drop_data to also mark all segments of the datasource unused, including data ingested by other supervisors. terminate_and_drop_data is refused while other supervisors feed the datasource",
+			ValidateFunc: validation.StringInSlice([]string{"terminate", "suspend_only", "terminate_and_drop_data"}, false),
+		},
+		
+		"kill_data_on_delete": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "With deletion_policy terminate_and_drop_data, also issue a kill task that permanently deletes all segments of the datasource from deep storage",
+		},
+		
+		"wait_for_healthy": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to wait for the supervisor to reach a healthy state after create and update",
+		},
+		
+		// Computed fields
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Current state of the supervisor",
+		},
+		
+		"detailed_state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Detailed state of the supervisor (e.g., CONNECTING_TO_STREAM, UNABLE_TO_CONNECT_TO_STREAM)",
+		},
+		
+		"healthy": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether Druid reports the supervisor as healthy",
+		},
+		
+		"partitions": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of stream partitions being read",
+		},
+		
+		"active_tasks": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Tasks currently reading from the stream",
+			Elem:        supervisorTaskSchema(),
+		},
+		
+		"publishing_tasks": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Tasks currently publishing segments",
+			Elem:        supervisorTaskSchema(),
+		},
+		
+		"aggregate_lag": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Total lag across all partitions",
+		},
+		
+		"minimum_lag": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "Minimum lag per partition",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		
+		"offsets_last_updated": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Time the latest stream offsets were last fetched",
+		},
+		
+		"recent_errors": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Recent errors reported by the supervisor",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"timestamp": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Time the error occurred",
+					},
+					"exception_class": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Java exception class of the error",
+					},
+					"message": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Error message",
+					},
+					"stream_exception": {
+						Type:        schema.TypeBool,
+						Computed:    true,
+						Description: "Whether the error came from the stream",
+					},
+				},
 			},
-			
-			// Computed fields
-			"supervisor_id": {
+		},
+	}
+}
+
+// resourceKafkaSupervisorV0 returns the schema of state version 0, in which
+// index spec bitmaps were string maps.
+func resourceKafkaSupervisorV0() *schema.Resource {
+	s := resourceKafkaSupervisorSchema()
+	tuningConfig := s["tuning_config"].Elem.(*schema.Resource)
+	for _, key := range indexSpecAttributes {
+		tuningConfig.Schema[key].Elem.(*schema.Resource).Schema["bitmap"] = &schema.Schema{
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		}
+	}
+	return &schema.Resource{Schema: s}
+}
+
+// resourceKafkaSupervisorStateUpgradeV0 converts index spec bitmaps from
+// string maps into blocks.
+func resourceKafkaSupervisorStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	tuningConfigs, _ := rawState["tuning_config"].([]interface{})
+	for _, tuningConfig := range tuningConfigs {
+		tc, ok := tuningConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range indexSpecAttributes {
+			indexSpecs, _ := tc[key].([]interface{})
+			for _, indexSpec := range indexSpecs {
+				if is, ok := indexSpec.(map[string]interface{}); ok {
+					is["bitmap"] = upgradeIndexSpecBitmapV0(is["bitmap"])
+				}
+			}
+		}
+	}
+	return rawState, nil
+}
+
+func supervisorTaskSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "The supervisor ID returned by Druid",
+				Description: "Task ID",
 			},
-			
-			"state": {
+			"start_time": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "Current state of the supervisor",
+				Description: "Time the task started",
+			},
+			"remaining_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds until the task stops reading",
 			},
 		},
 	}
@@ -479,7 +829,25 @@ func resourceKafkaSupervisor() *schema.Resource {
 func resourceKafkaSupervisorCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	
-	spec, err := buildSupervisorSpec(d)
+	// Posting a spec for an existing supervisor silently replaces it, which
+	// would also let create_before_destroy terminate the new supervisor when
+	// the old one is destroyed. A suspended supervisor of the same datasource
+	// is the one a suspend_only destroy left behind, and is taken over.
+	existing, err := client.GetSupervisorSpec(ctx, expectedSupervisorID(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if existing != nil {
+		leftover, err := isSuspendedLeftover(ctx, client, expectedSupervisorID(d), existing, d.Get("datasource").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !leftover {
+			return diag.Errorf("supervisor %s already exists; import it with terraform import to manage it", expectedSupervisorID(d))
+		}
+	}
+	
+	spec, err := supervisorSpecForApply(ctx, client, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -489,316 +857,2006 @@ func resourceKafkaSupervisorCreate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 	
+	// A supervisor under another ID was not asked for, so it is terminated
+	// rather than left ingesting outside of Terraform.
+	if requested := d.Get("supervisor_id").(string); requested != "" && requested != supervisorID {
+		if err := client.DeleteSupervisor(ctx, supervisorID); err != nil {
+			d.SetId(supervisorID)
+			return diag.Errorf("Druid created supervisor %s instead of the requested supervisor_id %s, and terminating it failed: %s", supervisorID, requested, err)
+		}
+		return diag.Errorf("Druid created supervisor %s instead of the requested supervisor_id %s and it was terminated; this Druid version may not support explicit supervisor IDs", supervisorID, requested)
+	}
+	
 	d.SetId(supervisorID)
 	d.Set("supervisor_id", supervisorID)
 	
+	if d.Get("wait_for_healthy").(bool) {
+		if err := waitForSupervisorHealthy(ctx, client, supervisorID, d.Timeout(schema.TimeoutCreate), nil); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	
 	return resourceKafkaSupervisorRead(ctx, d, meta)
 }
 
-func resourceKafkaSupervisorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*Client)
-	
-	supervisor, err := client.GetSupervisor(ctx, d.Id())
-	if err != nil {
-		return diag.FromErr(err)
+// isSuspendedLeftover reports whether an existing supervisor is suspended and
+// ingests into datasource, as a suspend_only destroy leaves it.
+func isSuspendedLeftover(ctx context.Context, client *Client, supervisorID string, spec map[string]interface{}, datasource string) (bool, error) {
+	if specDatasource(spec) != datasource {
+		return false, nil
 	}
 	
-	if supervisor == nil {
-		d.SetId("")
-		return nil
+	status, err := client.GetSupervisor(ctx, supervisorID)
+	if err != nil {
+		return false, err
 	}
-	
-	d.Set("state", supervisor.State)
-	d.Set("supervisor_id", supervisor.ID)
-	
-	return nil
+	return status != nil && status.State == "SUSPENDED", nil
 }
 
-func resourceKafkaSupervisorUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func resourceKafkaSupervisorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	
-	spec, err := buildSupervisorSpec(d)
+	spec, err := client.GetSupervisorSpec(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	
-	_, err = client.CreateSupervisor(ctx, spec)
+	if spec == nil {
+		// A supervisor terminated by a destroy that failed to drop its data
+		// is kept so the destroy can be retried.
+		if d.Get("state").(string) != terminatedSupervisorState {
+			d.SetId("")
+		}
+		return nil
+	}
+	
+	// A pinned historical spec is authoritative, so the typed attributes are
+	// not read back while spec_version is set. Drift from the pinned spec
+	// clears spec_version instead, so the next plan resubmits it.
+	if version := d.Get("spec_version").(string); version == "" {
+		if err := flattenSupervisorSpec(d, spec, false); err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		pinned, err := pinnedSupervisorSpec(ctx, client, d.Id(), version)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if pinned == nil || !reflect.DeepEqual(pinned["spec"], spec["spec"]) {
+			d.Set("spec_version", "")
+		}
+	}
+	
+	supervisor, err := client.GetSupervisor(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	
+	if supervisor == nil {
+		d.SetId("")
+		return nil
+	}
+	
+	d.Set("state", supervisor.State)
+	d.Set("supervisor_id", supervisor.ID)
+	d.Set("suspended", supervisor.State == "SUSPENDED")
+	
+	if err := flattenSupervisorStatus(d, supervisor); err != nil {
+		return diag.FromErr(err)
+	}
+	
+	return nil
+}
+
+func resourceKafkaSupervisorUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	
+	// The resubmitted spec carries the suspended flag, so the dedicated
+	// endpoints are only needed when nothing else changed. Provider-only
+	// settings never reach Druid, and resubmitting restarts every task.
+	resubmit := d.HasChangesExcept(append([]string{"suspended"}, providerOnlyAttributes...)...)
+	toggleSuspended := !resubmit && d.HasChange("suspended")
+	
+	// Only a change sent to Druid is waited for, and the supervisor it
+	// replaces is recorded first so its status does not end the wait.
+	wait := d.Get("wait_for_healthy").(bool) && (resubmit || toggleSuspended)
+	var previous *supervisorSnapshot
+	if wait {
+		var err error
+		previous, err = snapshotSupervisor(ctx, client, d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	
+	if resubmit {
+		spec, err := supervisorSpecForApply(ctx, client, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		
+		supervisorID, err := client.CreateSupervisor(ctx, spec)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		
+		if err := adoptSupervisorID(ctx, client, d, supervisorID); err != nil {
+			return diag.FromErr(err)
+		}
+		
+		// An equivalent spec leaves Druid serving the same spec as before,
+		// so only the state could tell the replaced supervisor apart.
+		if previous != nil {
+			live, err := client.GetSupervisorSpec(ctx, d.Id())
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if reflect.DeepEqual(live, previous.Spec) {
+				previous = nil
+			}
+		}
+	} else if toggleSuspended {
+		var err error
+		if d.Get("suspended").(bool) {
+			err = client.SuspendSupervisor(ctx, d.Id())
+		} else {
+			err = client.ResumeSupervisor(ctx, d.Id())
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	
+	if wait {
+		if err := waitForSupervisorHealthy(ctx, client, d.Id(), d.Timeout(schema.TimeoutUpdate), previous); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	
 	return resourceKafkaSupervisorRead(ctx, d, meta)
 }
 
+// providerOnlyAttributes configure the provider's own behaviour and are not
+// part of the supervisor spec.
+var providerOnlyAttributes = []string{"deletion_policy", "kill_data_on_delete", "wait_for_healthy"}
+
 func resourceKafkaSupervisorDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client)
 	
-	err := client.DeleteSupervisor(ctx, d.Id())
-	if err != nil {
-		return diag.FromErr(err)
+	switch d.Get("deletion_policy").(string) {
+	case "suspend_only":
+		status, err := client.GetSupervisor(ctx, d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if status != nil && status.State != "SUSPENDED" {
+			if err := client.SuspendSupervisor(ctx, d.Id()); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	case "terminate_and_drop_data":
+		// Dropping data affects the whole datasource, so it is refused while
+		// other supervisors still ingest into it.
+		datasource := d.Get("datasource").(string)
+		others, err := otherDatasourceSupervisors(ctx, client, datasource, d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(others) > 0 {
+			return diag.Errorf("datasource %s is also fed by supervisors %s; deletion_policy terminate_and_drop_data would drop their data too, use terminate instead", datasource, strings.Join(others, ", "))
+		}
+		
+		// Terminating lets the supervisor's tasks publish what they hold, so
+		// segments are only marked unused once those tasks have finished.
+		status, err := client.GetSupervisor(ctx, d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		
+		// A supervisor that is already gone was terminated by an earlier
+		// attempt, which DeleteSupervisor treats as done.
+		if err := client.DeleteSupervisor(ctx, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+		
+		// If dropping fails, the resource stays in state as terminated so the
+		// next destroy retries the drop instead of Read discarding it.
+		if err := dropSupervisorData(ctx, client, d, status); err != nil {
+			d.Set("state", terminatedSupervisorState)
+			return diag.FromErr(err)
+		}
+	default:
+		if err := client.DeleteSupervisor(ctx, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 	
 	d.SetId("")
 	return nil
 }
 
-func buildSupervisorSpec(d *schema.ResourceData) (map[string]interface{}, error) {
-	spec := map[string]interface{}{
-		"type": "kafka",
-		"spec": map[string]interface{}{
-			"dataSchema": buildDataSchema(d),
-			"ioConfig":   buildIOConfig(d),
-		},
+// terminatedSupervisorState marks a supervisor that was terminated but whose
+// datasource has not been dropped yet.
+const terminatedSupervisorState = "TERMINATED"
+
+// dropSupervisorData waits for the tasks of a terminated supervisor to finish
+// and then marks its datasource's segments unused, killing them if requested.
+func dropSupervisorData(ctx context.Context, client *Client, d *schema.ResourceData, status *SupervisorStatus) error {
+	if status != nil {
+		if err := waitForTasksFinished(ctx, client, supervisorTaskIDs(status), d.Timeout(schema.TimeoutDelete)); err != nil {
+			return err
+		}
 	}
 	
-	if tuningConfig := buildTuningConfig(d); tuningConfig != nil {
-		spec["spec"].(map[string]interface{})["tuningConfig"] = tuningConfig
+	datasource := d.Get("datasource").(string)
+	if err := client.MarkDatasourceUnused(ctx, datasource); err != nil {
+		return err
+	}
+	if d.Get("kill_data_on_delete").(bool) {
+		return client.KillDatasource(ctx, datasource)
+	}
+	return nil
+}
+
+// supervisorTaskIDs returns the IDs of a supervisor's reading and publishing
+// tasks.
+func supervisorTaskIDs(status *SupervisorStatus) []string {
+	var taskIDs []string
+	for _, task := range append(append([]SupervisorTask{}, status.ActiveTasks...), status.PublishingTasks...) {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	return taskIDs
+}
+
+// waitForTasksFinished waits until none of the tasks is still running.
+func waitForTasksFinished(ctx context.Context, client *Client, taskIDs []string, timeout time.Duration) error {
+	if len(taskIDs) == 0 {
+		return nil
 	}
 	
-	if context := d.Get("context").(map[string]interface{}); len(context) > 0 {
-		spec["spec"].(map[string]interface{})["context"] = context
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"RUNNING"},
+		Target:  []string{"FINISHED"},
+		Timeout: timeout,
+		Refresh: func() (interface{}, string, error) {
+			for _, taskID := range taskIDs {
+				statusCode, err := client.GetTaskStatus(ctx, taskID)
+				if err != nil {
+					return nil, "", err
+				}
+				if statusCode == "RUNNING" {
+					return taskID, "RUNNING", nil
+				}
+			}
+			return "", "FINISHED", nil
+		},
 	}
 	
-	if suspended := d.Get("suspended").(bool); suspended {
-		spec["spec"].(map[string]interface{})["suspended"] = suspended
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the supervisor's tasks to finish: %w", err)
 	}
 	
-	return spec, nil
+	return nil
 }
 
-func buildDataSchema(d *schema.ResourceData) map[string]interface{} {
-	dataSchema := map[string]interface{}{
-		"dataSource": d.Get("datasource").(string),
+// otherDatasourceSupervisors returns the IDs of the active supervisors other
+// than supervisorID that ingest into datasource.
+func otherDatasourceSupervisors(ctx context.Context, client *Client, datasource, supervisorID string) ([]string, error) {
+	supervisors, err := client.ListSupervisors(ctx)
+	if err != nil {
+		return nil, err
 	}
 	
-	// Timestamp spec
-	if timestampSpecs := d.Get("timestamp_spec").([]interface{}); len(timestampSpecs) > 0 {
-		timestampSpec := timestampSpecs[0].(map[string]interface{})
-		ts := map[string]interface{}{
-			"column": timestampSpec["column"].(string),
-			"format": timestampSpec["format"].(string),
-		}
-		if missingValue := timestampSpec["missing_value"].(string); missingValue != "" {
-			ts["missingValue"] = missingValue
+	var others []string
+	for _, supervisor := range supervisors {
+		if supervisor.ID != supervisorID && specDatasource(supervisor.Spec) == datasource {
+			others = append(others, supervisor.ID)
 		}
-		dataSchema["timestampSpec"] = ts
+	}
+	sort.Strings(others)
+	return others, nil
+}
+
+// specDatasource returns the datasource a supervisor spec ingests into.
+func specDatasource(spec map[string]interface{}) string {
+	ingestionSpec := spec
+	if s, ok := spec["spec"].(map[string]interface{}); ok {
+		ingestionSpec = s
+	}
+	dataSchema, _ := ingestionSpec["dataSchema"].(map[string]interface{})
+	return flattenString(dataSchema["dataSource"])
+}
+
+// Supervisor states reported while Druid is still bringing up ingestion tasks
+// or stopping the supervisor being replaced
+var pendingSupervisorStates = []string{
+	"PENDING",
+	"CONNECTING_TO_STREAM",
+	"DISCOVERING_INITIAL_TASKS",
+	"CREATING_TASKS",
+	"STOPPING",
+	updatingSupervisorState,
+}
+
+// updatingSupervisorState is reported while the status still appears to come
+// from the supervisor an update replaced
+const updatingSupervisorState = "UPDATING"
+
+// Supervisor states that are considered healthy once reached
+var healthySupervisorStates = []string{
+	"RUNNING",
+	"IDLE",
+	"SUSPENDED",
+}
+
+// Supervisor states that fail the apply immediately
+var unhealthySupervisorStates = map[string]bool{
+	"UNHEALTHY_SUPERVISOR":        true,
+	"UNHEALTHY_TASKS":             true,
+	"UNABLE_TO_CONNECT_TO_STREAM": true,
+	"LOST_CONTACT_WITH_STREAM":    true,
+}
+
+// supervisorSnapshot is a supervisor's state and spec taken before an update.
+type supervisorSnapshot struct {
+	State string
+	Spec  map[string]interface{}
+}
+
+// snapshotSupervisor records the current state and spec of a supervisor, or
+// returns nil if it does not exist.
+func snapshotSupervisor(ctx context.Context, client *Client, supervisorID string) (*supervisorSnapshot, error) {
+	status, err := client.GetSupervisor(ctx, supervisorID)
+	if err != nil || status == nil {
+		return nil, err
 	}
 	
-	// Dimensions spec
-	if dimensionsSpecs := d.Get("dimensions_spec").([]interface{}); len(dimensionsSpecs) > 0 {
-		dimensionsSpec := dimensionsSpecs[0].(map[string]interface{})
-		ds := map[string]interface{}{}
-		
-		if dimensions := dimensionsSpec["dimensions"].([]interface{}); len(dimensions) > 0 {
-			var dims []interface{}
-			for _, dim := range dimensions {
-				dimMap := dim.(map[string]interface{})
-				d := map[string]interface{}{
-					"name": dimMap["name"].(string),
-					"type": dimMap["type"].(string),
-				}
-				if mvh := dimMap["multi_value_handling"].(string); mvh != "" {
-					d["multiValueHandling"] = mvh
+	spec, err := client.GetSupervisorSpec(ctx, supervisorID)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &supervisorSnapshot{State: status.State, Spec: spec}, nil
+}
+
+// waitForSupervisorHealthy waits for a supervisor to reach a healthy state.
+// After an update, previous holds the supervisor as it was before, and its
+// state is only accepted once the state has changed or Druid serves a
+// different spec, so a stale status of the replaced supervisor does not pass.
+func waitForSupervisorHealthy(ctx context.Context, client *Client, supervisorID string, timeout time.Duration, previous *supervisorSnapshot) error {
+	updated := previous == nil
+	stateConf := &retry.StateChangeConf{
+		Pending: pendingSupervisorStates,
+		Target:  healthySupervisorStates,
+		Timeout: timeout,
+		Refresh: func() (interface{}, string, error) {
+			status, err := client.GetSupervisor(ctx, supervisorID)
+			if err != nil {
+				return nil, "", err
+			}
+			
+			if status == nil {
+				return nil, "", fmt.Errorf("supervisor %s not found", supervisorID)
+			}
+			
+			if unhealthySupervisorStates[status.State] || unhealthySupervisorStates[status.DetailedState] {
+				state := status.State
+				if status.DetailedState != "" {
+					state = status.DetailedState
 				}
-				dims = append(dims, d)
+				return status, status.State, fmt.Errorf("supervisor %s is in state %s%s", supervisorID, state, formatSupervisorErrors(status.RecentErrors))
 			}
-			ds["dimensions"] = dims
-		}
-		
-		if exclusions := dimensionsSpec["dimension_exclusions"].([]interface{}); len(exclusions) > 0 {
-			ds["dimensionExclusions"] = exclusions
-		}
-		
-		if spatialDims := dimensionsSpec["spatial_dimensions"].([]interface{}); len(spatialDims) > 0 {
-			var spatialDimensions []interface{}
-			for _, spatial := range spatialDims {
-				spatialMap := spatial.(map[string]interface{})
-				spatialDimensions = append(spatialDimensions, map[string]interface{}{
-					"dimName": spatialMap["dim_name"].(string),
-					"dims":    spatialMap["dims"].([]interface{}),
-				})
+			
+			if !updated {
+				if status.State != previous.State {
+					updated = true
+				} else {
+					spec, err := client.GetSupervisorSpec(ctx, supervisorID)
+					if err != nil {
+						return nil, "", err
+					}
+					if !reflect.DeepEqual(spec, previous.Spec) {
+						updated = true
+					}
+				}
+				if !updated {
+					return status, updatingSupervisorState, nil
+				}
 			}
-			ds["spatialDimensions"] = spatialDimensions
-		}
-		
-		if len(ds) > 0 {
-			dataSchema["dimensionsSpec"] = ds
-		}
+			
+			return status, status.State, nil
+		},
 	}
 	
-	// Metrics spec
-	if metricsSpecs := d.Get("metrics_spec").([]interface{}); len(metricsSpecs) > 0 {
-		var metrics []interface{}
-		for _, metric := range metricsSpecs {
-			metricMap := metric.(map[string]interface{})
-			m := map[string]interface{}{
-				"name": metricMap["name"].(string),
-				"type": metricMap["type"].(string),
-			}
-			if fieldName := metricMap["field_name"].(string); fieldName != "" {
-				m["fieldName"] = fieldName
-			}
-			metrics = append(metrics, m)
-		}
-		dataSchema["metricsSpec"] = metrics
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for supervisor %s to become healthy: %w", supervisorID, err)
 	}
 	
-	// Granularity spec
-	if granularitySpecs := d.Get("granularity_spec").([]interface{}); len(granularitySpecs) > 0 {
-		granularitySpec := granularitySpecs[0].(map[string]interface{})
-		gs := map[string]interface{}{
-			"type":               granularitySpec["type"].(string),
-			"segmentGranularity": granularitySpec["segment_granularity"].(string),
-			"queryGranularity":   granularitySpec["query_granularity"].(string),
-			"rollup":             granularitySpec["rollup"].(bool),
-		}
-		dataSchema["granularitySpec"] = gs
+	return nil
+}
+
+func formatSupervisorErrors(recentErrors []SupervisorError) string {
+	if len(recentErrors) == 0 {
+		return ""
 	}
 	
-	return dataSchema
+	var b strings.Builder
+	b.WriteString("; recent errors:")
+	for _, e := range recentErrors {
+		fmt.Fprintf(&b, "\n  [%s] %s: %s", e.Timestamp, e.ExceptionClass, e.Message)
+	}
+	return b.String()
 }
 
-func buildIOConfig(d *schema.ResourceData) map[string]interface{} {
-	ioConfig := map[string]interface{}{}
+func resourceKafkaSupervisorImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*Client)
 	
-	// Topic or topic pattern
-	if topic := d.Get("topic").(string); topic != "" {
-		ioConfig["topic"] = topic
+	spec, err := client.GetSupervisorSpec(ctx, d.Id())
+	if err != nil {
+		return nil, err
 	}
-	if topicPattern := d.Get("topic_pattern").(string); topicPattern != "" {
-		ioConfig["topicPattern"] = topicPattern
+	
+	if spec == nil {
+		return nil, fmt.Errorf("supervisor %s not found", d.Id())
 	}
 	
-	// Input format
-	if inputFormats := d.Get("input_format").([]interface{}); len(inputFormats) > 0 {
-		inputFormat := inputFormats[0].(map[string]interface{})
-		inputFormatConfig := map[string]interface{}{
-			"type": inputFormat["type"].(string),
-		}
-		
-		if flatSpecs := inputFormat["flat_spec"].([]interface{}); len(flatSpecs) > 0 {
-			flatSpec := flatSpecs[0].(map[string]interface{})
-			fs := map[string]interface{}{
-				"useFieldDiscovery": flatSpec["use_field_discovery"].(bool),
+	if err := flattenSupervisorSpec(d, spec, true); err != nil {
+		return nil, err
+	}
+	d.Set("supervisor_id", d.Id())
+	
+	// Provider-only settings are never read from Druid, so start from defaults
+	d.Set("wait_for_healthy", true)
+	d.Set("deletion_policy", "terminate")
+	d.Set("kill_data_on_delete", false)
+	
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceKafkaSupervisorCustomizeDiff validates settings that depend on each
+// other at plan time. Blocks that are not yet fully known are skipped.
+func resourceKafkaSupervisorCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if configKnown(d, "transform_spec") {
+		if filters := d.Get("transform_spec.0.filter").([]interface{}); len(filters) > 0 && filters[0] != nil {
+			if err := validateFilter(filters[0].(map[string]interface{}), "transform_spec.0.filter.0"); err != nil {
+				return err
 			}
-			if delimiter := flatSpec["delimiter"].(string); delimiter != "" {
-				fs["delimiter"] = delimiter
+		}
+	}
+	
+	if configKnown(d, "dimensions_spec") {
+		if dimensionsSpec := firstBlock(d.Get("dimensions_spec")); dimensionsSpec != nil {
+			if err := validateDimensionsSpec(dimensionsSpec, "dimensions_spec.0"); err != nil {
+				return err
 			}
-			if columns := flatSpec["columns"].([]interface{}); len(columns) > 0 {
-				fs["columns"] = columns
+		}
+	}
+	
+	if configKnown(d, "metrics_spec") {
+		for i, metric := range d.Get("metrics_spec").([]interface{}) {
+			if metricMap, ok := metric.(map[string]interface{}); ok {
+				if err := validateAggregator(metricMap, fmt.Sprintf("metrics_spec.%d", i)); err != nil {
+					return err
+				}
 			}
-			inputFormatConfig["flatSpec"] = fs
 		}
-		
-		ioConfig["inputFormat"] = inputFormatConfig
 	}
 	
-	// Consumer properties
-	if consumerProps := d.Get("consumer_properties").(map[string]interface{}); len(consumerProps) > 0 {
-		ioConfig["consumerProperties"] = consumerProps
+	if configKnown(d, "granularity_spec") {
+		if granularitySpec := firstBlock(d.Get("granularity_spec")); granularitySpec != nil {
+			if err := validateGranularitySpec(granularitySpec, "granularity_spec.0"); err != nil {
+				return err
+			}
+		}
 	}
 	
-	// Task configuration
-	ioConfig["taskCount"] = d.Get("task_count").(int)
-	ioConfig["replicas"] = d.Get("replicas").(int)
-	ioConfig["taskDuration"] = d.Get("task_duration").(string)
-	ioConfig["useEarliestOffset"] = d.Get("use_earliest_offset").(bool)
-	ioConfig["completionTimeout"] = d.Get("completion_timeout").(string)
+	if configKnown(d, "autoscaler_config") && configKnown(d, "task_count") {
+		if autoScalerConfig := firstBlock(d.Get("autoscaler_config")); autoScalerConfig != nil {
+			taskCountSet := !d.GetRawConfig().GetAttr("task_count").IsNull()
+			if err := validateAutoScalerConfig(autoScalerConfig, d.Get("task_count").(int), taskCountSet, "autoscaler_config.0"); err != nil {
+				return err
+			}
+		}
+	}
 	
-	// Idle configuration
-	if idleConfigs := d.Get("idle_config").([]interface{}); len(idleConfigs) > 0 {
-		idleConfig := idleConfigs[0].(map[string]interface{})
-		ic := map[string]interface{}{
-			"enabled": idleConfig["enabled"].(bool),
+	if configKnown(d, "tuning_config") {
+		if tuningConfig := firstBlock(d.Get("tuning_config")); tuningConfig != nil {
+			configured := func(key string) bool {
+				return blockAttrConfigured(d.GetRawConfig(), "tuning_config", key)
+			}
+			if err := validateTuningConfig(tuningConfig, configured, "tuning_config.0"); err != nil {
+				return err
+			}
 		}
-		if inactiveAfter := idleConfig["inactive_after_millis"].(int); inactiveAfter > 0 {
-			ic["inactiveAfterMillis"] = inactiveAfter
+	}
+	
+	if configKnown(d, "input_format") {
+		if inputFormats := d.Get("input_format").([]interface{}); len(inputFormats) > 0 && inputFormats[0] != nil {
+			if err := validateInputFormat(inputFormats[0].(map[string]interface{}), "input_format.0"); err != nil {
+				return err
+			}
 		}
-		ioConfig["idleConfig"] = ic
 	}
 	
-	return ioConfig
+	return nil
 }
 
-func buildTuningConfig(d *schema.ResourceData) map[string]interface{} {
-	if tuningConfigs := d.Get("tuning_config").([]interface{}); len(tuningConfigs) > 0 {
-		tuningConfig := tuningConfigs[0].(map[string]interface{})
-		tc := map[string]interface{}{
-			"type": "kafka",
-		}
-		
-		if maxRows := tuningConfig["max_rows_per_segment"].(int); maxRows > 0 {
-			tc["maxRowsPerSegment"] = maxRows
+// validateTuningConfig checks the index specs and the parse exception
+// options against each other. configured reports whether an option is set
+// explicitly.
+func validateTuningConfig(tuningConfig map[string]interface{}, configured func(key string) bool, path string) error {
+	for _, key := range indexSpecAttributes {
+		if indexSpec := firstBlock(tuningConfig[key]); indexSpec != nil {
+			if err := validateIndexSpec(indexSpec, fmt.Sprintf("%s.%s.0", path, key)); err != nil {
+				return err
+			}
 		}
-		if maxRowsInMemory := tuningConfig["max_rows_in_memory"].(int); maxRowsInMemory > 0 {
-			tc["maxRowsInMemory"] = maxRowsInMemory
+	}
+	
+	maxParseExceptions, _ := tuningConfig["max_parse_exceptions"].(int)
+	maxSavedParseExceptions, _ := tuningConfig["max_saved_parse_exceptions"].(int)
+	
+	if reportExceptions, _ := tuningConfig["report_parse_exceptions"].(bool); reportExceptions {
+		if configured("max_parse_exceptions") && maxParseExceptions != 0 {
+			return fmt.Errorf("%s: report_parse_exceptions sets max_parse_exceptions to 0, so max_parse_exceptions (%d) would be ignored", path, maxParseExceptions)
 		}
-		if maxBytesInMemory := tuningConfig["max_bytes_in_memory"].(int); maxBytesInMemory > 0 {
-			tc["maxBytesInMemory"] = maxBytesInMemory
+		if maxSavedParseExceptions > 1 {
+			return fmt.Errorf("%s: report_parse_exceptions limits max_saved_parse_exceptions to 1, got %d", path, maxSavedParseExceptions)
 		}
-		if skipCheck := tuningConfig["skip_bytes_in_memory_overhead_check"].(bool); skipCheck {
-			tc["skipBytesInMemoryOverheadCheck"] = skipCheck
+		return nil
+	}
+	
+	// Ingestion fails on the exception after max_parse_exceptions, so no
+	// more than one more than that can ever be saved.
+	if configured("max_parse_exceptions") && maxSavedParseExceptions > maxParseExceptions+1 {
+		return fmt.Errorf("%s: max_saved_parse_exceptions (%d) can never be reached with max_parse_exceptions (%d)", path, maxSavedParseExceptions, maxParseExceptions)
+	}
+	
+	return nil
+}
+
+// validateDimensionsSpec checks that dimension names are unique and that
+// string-only options are only set on string dimensions.
+func validateDimensionsSpec(dimensionsSpec map[string]interface{}, path string) error {
+	dimensions, _ := dimensionsSpec["dimensions"].([]interface{})
+	
+	seen := map[string]bool{}
+	for i, dim := range dimensions {
+		dimMap, ok := dim.(map[string]interface{})
+		if !ok {
+			continue
 		}
-		if maxPending := tuningConfig["max_pending_persists"].(int); maxPending > 0 {
-			tc["maxPendingPersists"] = maxPending
+		name, _ := dimMap["name"].(string)
+		dimType, _ := dimMap["type"].(string)
+		
+		if seen[name] {
+			return fmt.Errorf("%s.dimensions.%d: dimension %q is listed more than once", path, i, name)
 		}
-		if intermediatePersist := tuningConfig["intermediate_persist_period"].(string); intermediatePersist != "" {
-			tc["intermediatePersistPeriod"] = intermediatePersist
+		seen[name] = true
+		
+		if dimType == "string" {
+			continue
 		}
-		if maxParseExceptions := tuningConfig["max_parse_exceptions"].(int); maxParseExceptions > 0 {
-			tc["maxParseExceptions"] = maxParseExceptions
+		if mvh, _ := dimMap["multi_value_handling"].(string); mvh != "" {
+			return fmt.Errorf("%s.dimensions.%d: multi_value_handling is only supported by string dimensions", path, i)
 		}
-		if maxSavedExceptions := tuningConfig["max_saved_parse_exceptions"].(int); maxSavedExceptions > 0 {
-			tc["maxSavedParseExceptions"] = maxSavedExceptions
+		if createBitmapIndex, ok := dimMap["create_bitmap_index"].(bool); ok && !createBitmapIndex {
+			return fmt.Errorf("%s.dimensions.%d: create_bitmap_index is only supported by string dimensions", path, i)
 		}
-		if logExceptions := tuningConfig["log_parse_exceptions"].(bool); logExceptions {
-			tc["logParseExceptions"] = logExceptions
+	}
+	
+	return nil
+}
+
+// configKnown reports whether the top-level attribute is known in the
+// configuration, so that values interpolated from other resources are not
+// validated as empty.
+func configKnown(d *schema.ResourceDiff, key string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	return config.GetAttr(key).IsWhollyKnown()
+}
+
+// blockAttrConfigured reports whether key is set in the configuration of the
+// single-item block named block, which tells explicit values from defaults.
+func blockAttrConfigured(config cty.Value, block, key string) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	blocks := config.GetAttr(block)
+	if blocks.IsNull() || !blocks.IsKnown() || blocks.LengthInt() == 0 {
+		return false
+	}
+	return !blocks.Index(cty.NumberIntVal(0)).GetAttr(key).IsNull()
+}
+
+// expectedSupervisorID returns the ID Druid assigns to the supervisor: the
+// configured supervisor_id, or else its datasource name.
+func expectedSupervisorID(d *schema.ResourceData) string {
+	if supervisorID := d.Get("supervisor_id").(string); supervisorID != "" {
+		return supervisorID
+	}
+	return d.Get("datasource").(string)
+}
+
+// adoptSupervisorID handles Druid answering a resubmitted spec with a
+// different supervisor ID: the supervisor previously tracked in state is
+// terminated so it doesn't keep running unmanaged, and state follows the new
+// ID.
+func adoptSupervisorID(ctx context.Context, client *Client, d *schema.ResourceData, supervisorID string) error {
+	if supervisorID == "" || supervisorID == d.Id() {
+		return nil
+	}
+	
+	if err := client.DeleteSupervisor(ctx, d.Id()); err != nil {
+		return fmt.Errorf("supervisor was resubmitted as %s, but terminating the previous supervisor %s failed: %w", supervisorID, d.Id(), err)
+	}
+	
+	d.SetId(supervisorID)
+	d.Set("supervisor_id", supervisorID)
+	
+	return nil
+}
+
+// supervisorSpecForApply returns the spec to submit to Druid: the pinned
+// historical version when spec_version is set, otherwise the spec built from
+// the configuration.
+func supervisorSpecForApply(ctx context.Context, client *Client, d *schema.ResourceData) (map[string]interface{}, error) {
+	version := d.Get("spec_version").(string)
+	if version == "" {
+		return buildSupervisorSpec(d)
+	}
+	
+	supervisorID := d.Id()
+	if supervisorID == "" {
+		supervisorID = expectedSupervisorID(d)
+	}
+	
+	spec, err := pinnedSupervisorSpec(ctx, client, supervisorID, version)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("spec version %s not found in the history of supervisor %s", version, supervisorID)
+	}
+	
+	spec["suspended"] = d.Get("suspended").(bool)
+	return spec, nil
+}
+
+// pinnedSupervisorSpec returns the spec of a version from the supervisor's
+// history, or nil when the history does not contain it.
+func pinnedSupervisorSpec(ctx context.Context, client *Client, supervisorID, version string) (map[string]interface{}, error) {
+	history, err := client.GetSupervisorHistory(ctx, supervisorID)
+	if err != nil {
+		return nil, err
+	}
+	
+	for _, entry := range history {
+		if entry.Version == version {
+			return entry.Spec, nil
 		}
-		if resetOffset := tuningConfig["reset_offset_automatically"].(bool); resetOffset {
-			tc["resetOffsetAutomatically"] = resetOffset
+	}
+	
+	return nil, nil
+}
+
+func buildSupervisorSpec(d *schema.ResourceData) (map[string]interface{}, error) {
+	spec := buildTypedSupervisorSpec(d)
+	
+	rawSpec, err := parseSpecJSON(d)
+	if err != nil {
+		return nil, err
+	}
+	mergeSpec(spec, rawSpec)
+	
+	return spec, nil
+}
+
+// buildTypedSupervisorSpec builds the spec from the typed attributes alone,
+// before spec_json is merged over it.
+func buildTypedSupervisorSpec(d *schema.ResourceData) map[string]interface{} {
+	spec := map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": buildDataSchema(d),
+			"ioConfig":   buildIOConfig(d),
+		},
+	}
+	
+	if supervisorID := d.Get("supervisor_id").(string); supervisorID != "" {
+		spec["id"] = supervisorID
+	}
+	
+	if tuningConfig := buildTuningConfig(d); tuningConfig != nil {
+		spec["spec"].(map[string]interface{})["tuningConfig"] = tuningConfig
+	}
+	
+	if context := d.Get("context").(map[string]interface{}); len(context) > 0 {
+		spec["spec"].(map[string]interface{})["context"] = context
+	}
+	
+	if suspended := d.Get("suspended").(bool); suspended {
+		spec["suspended"] = suspended
+	}
+	
+	return spec
+}
+
+// parseSpecJSON returns the spec_json overrides, or nil when none are set.
+func parseSpecJSON(d *schema.ResourceData) (map[string]interface{}, error) {
+	specJSON := d.Get("spec_json").(string)
+	if specJSON == "" {
+		return nil, nil
+	}
+	
+	var rawSpec map[string]interface{}
+	if err := json.Unmarshal([]byte(specJSON), &rawSpec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec_json: %w", err)
+	}
+	return rawSpec, nil
+}
+
+// mergeSpec deep-merges src into dst. Nested objects are merged recursively,
+// any other value replaces the one in dst and a null value removes the key.
+// Nested objects are copied before merging, since they may be shared with
+// the resource data.
+func mergeSpec(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if value == nil {
+			delete(dst, key)
+			continue
 		}
-		if workerThreads := tuningConfig["worker_threads"].(int); workerThreads > 0 {
-			tc["workerThreads"] = workerThreads
+		
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			merged := make(map[string]interface{}, len(dstMap))
+			for k, v := range dstMap {
+				merged[k] = v
+			}
+			mergeSpec(merged, srcMap)
+			dst[key] = merged
+			continue
 		}
-		if chatThreads := tuningConfig["chat_threads"].(int); chatThreads > 0 {
-			tc["chatThreads"] = chatThreads
+		
+		dst[key] = value
+	}
+}
+
+// unmergeSpec reverts the keys src sets in dst to their values in generated,
+// the inverse of mergeSpec. Keys missing from generated are removed.
+func unmergeSpec(dst, generated, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		generatedMap, generatedIsMap := generated[key].(map[string]interface{})
+		if srcIsMap && dstIsMap && generatedIsMap {
+			unmergeSpec(dstMap, generatedMap, srcMap)
+			continue
 		}
-		if chatRetries := tuningConfig["chat_retries"].(int); chatRetries > 0 {
-			tc["chatRetries"] = chatRetries
+		
+		if generatedValue, ok := generated[key]; ok {
+			dst[key] = generatedValue
+		} else {
+			delete(dst, key)
 		}
-		if httpTimeout := tuningConfig["http_timeout"].(string); httpTimeout != "" {
-			tc["httpTimeout"] = httpTimeout
+	}
+}
+
+// withoutSpecJSONOverrides returns a copy of the live spec in which the keys
+// set by spec_json hold the values generated from the typed attributes, so
+// that overrides are not read back into those attributes.
+func withoutSpecJSONOverrides(d *schema.ResourceData, supervisor map[string]interface{}) (map[string]interface{}, error) {
+	rawSpec, err := parseSpecJSON(d)
+	if err != nil || rawSpec == nil {
+		return supervisor, err
+	}
+	
+	var live map[string]interface{}
+	if err := copyJSON(supervisor, &live); err != nil {
+		return nil, err
+	}
+	unmergeSpec(live, buildTypedSupervisorSpec(d), rawSpec)
+	
+	// Round trip again so generated values are typed like Druid's.
+	var result map[string]interface{}
+	if err := copyJSON(live, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// copyJSON copies src into dst through its JSON encoding.
+func copyJSON(src interface{}, dst interface{}) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func buildDataSchema(d *schema.ResourceData) map[string]interface{} {
+	dataSchema := map[string]interface{}{
+		"dataSource": d.Get("datasource").(string),
+	}
+	
+	// Timestamp spec
+	if timestampSpecs := d.Get("timestamp_spec").([]interface{}); len(timestampSpecs) > 0 {
+		timestampSpec := timestampSpecs[0].(map[string]interface{})
+		ts := map[string]interface{}{
+			"column": timestampSpec["column"].(string),
+			"format": timestampSpec["format"].(string),
 		}
-		if shutdownTimeout := tuningConfig["shutdown_timeout"].(string); shutdownTimeout != "" {
-			tc["shutdownTimeout"] = shutdownTimeout
+		if missingValue := timestampSpec["missing_value"].(string); missingValue != "" {
+			ts["missingValue"] = missingValue
 		}
+		dataSchema["timestampSpec"] = ts
+	}
+	
+	// Dimensions spec
+	if dimensionsSpecs := d.Get("dimensions_spec").([]interface{}); len(dimensionsSpecs) > 0 {
+		dimensionsSpec := dimensionsSpecs[0].(map[string]interface{})
+		ds := map[string]interface{}{}
 		
-		// Index spec
-		if indexSpecs := tuningConfig["index_spec"].([]interface{}); len(indexSpecs) > 0 {
-			indexSpec := indexSpecs[0].(map[string]interface{})
-			is := map[string]interface{}{}
-			
-			if bitmap := indexSpec["bitmap"].(map[string]interface{}); len(bitmap) > 0 {
-				is["bitmap"] = bitmap
-			}
-			if dimCompression := indexSpec["dimension_compression"].(string); dimCompression != "" {
-				is["dimensionCompression"] = dimCompression
+		if dimensions := dimensionsSpec["dimensions"].([]interface{}); len(dimensions) > 0 {
+			var dims []interface{}
+			for _, dim := range dimensions {
+				dimMap := dim.(map[string]interface{})
+				d := map[string]interface{}{
+					"name": dimMap["name"].(string),
+					"type": dimMap["type"].(string),
+				}
+				if mvh := dimMap["multi_value_handling"].(string); mvh != "" {
+					d["multiValueHandling"] = mvh
+				}
+				if createBitmapIndex := dimMap["create_bitmap_index"].(bool); !createBitmapIndex {
+					d["createBitmapIndex"] = createBitmapIndex
+				}
+				dims = append(dims, d)
 			}
-			if metricCompression := indexSpec["metric_compression"].(string); metricCompression != "" {
-				is["metricCompression"] = metricCompression
+			ds["dimensions"] = dims
+		}
+		
+		if exclusions := dimensionsSpec["dimension_exclusions"].([]interface{}); len(exclusions) > 0 {
+			ds["dimensionExclusions"] = exclusions
+		}
+		
+		if spatialDims := dimensionsSpec["spatial_dimensions"].([]interface{}); len(spatialDims) > 0 {
+			var spatialDimensions []interface{}
+			for _, spatial := range spatialDims {
+				spatialMap := spatial.(map[string]interface{})
+				spatialDimensions = append(spatialDimensions, map[string]interface{}{
+					"dimName": spatialMap["dim_name"].(string),
+					"dims":    spatialMap["dims"].([]interface{}),
+				})
 			}
-			
-			if len(is) > 0 {
-				tc["indexSpec"] = is
+			ds["spatialDimensions"] = spatialDimensions
+		}
+		
+		if useSchemaDiscovery := dimensionsSpec["use_schema_discovery"].(bool); useSchemaDiscovery {
+			ds["useSchemaDiscovery"] = useSchemaDiscovery
+		}
+		if includeAll := dimensionsSpec["include_all_dimensions"].(bool); includeAll {
+			ds["includeAllDimensions"] = includeAll
+		}
+		
+		if len(ds) > 0 {
+			dataSchema["dimensionsSpec"] = ds
+		}
+	}
+	
+	// Metrics spec
+	if metricsSpecs := d.Get("metrics_spec").([]interface{}); len(metricsSpecs) > 0 {
+		var metrics []interface{}
+		for _, metric := range metricsSpecs {
+			metrics = append(metrics, buildAggregator(metric.(map[string]interface{})))
+		}
+		dataSchema["metricsSpec"] = metrics
+	}
+	
+	// Granularity spec
+	if granularitySpecs := d.Get("granularity_spec").([]interface{}); len(granularitySpecs) > 0 {
+		granularitySpec := granularitySpecs[0].(map[string]interface{})
+		gsType := granularitySpec["type"].(string)
+		gs := map[string]interface{}{
+			"type":             gsType,
+			"queryGranularity": buildGranularity(granularitySpec["query_granularity"].(string), granularitySpec["query_granularity_period"]),
+			"rollup":           granularitySpec["rollup"].(bool),
+		}
+		// Arbitrary granularity specs derive segments from the intervals.
+		if gsType != "arbitrary" {
+			gs["segmentGranularity"] = buildGranularity(granularitySpec["segment_granularity"].(string), granularitySpec["segment_granularity_period"])
+		}
+		if intervals, ok := granularitySpec["intervals"].([]interface{}); ok && len(intervals) > 0 {
+			gs["intervals"] = intervals
+		}
+		dataSchema["granularitySpec"] = gs
+	}
+	
+	// Transform spec
+	if transformSpecs := d.Get("transform_spec").([]interface{}); len(transformSpecs) > 0 && transformSpecs[0] != nil {
+		transformSpec := transformSpecs[0].(map[string]interface{})
+		ts := map[string]interface{}{}
+		
+		if transforms := transformSpec["transforms"].([]interface{}); len(transforms) > 0 {
+			var tfs []interface{}
+			for _, transform := range transforms {
+				transformMap := transform.(map[string]interface{})
+				tfs = append(tfs, map[string]interface{}{
+					"type":       transformMap["type"].(string),
+					"name":       transformMap["name"].(string),
+					"expression": transformMap["expression"].(string),
+				})
 			}
+			ts["transforms"] = tfs
 		}
 		
-		if segmentWriteOut := tuningConfig["segment_write_out_medium_factory"].(map[string]interface{}); len(segmentWriteOut) > 0 {
-			tc["segmentWriteOutMediumFactory"] = segmentWriteOut
+		if filters := transformSpec["filter"].([]interface{}); len(filters) > 0 {
+			ts["filter"] = buildFilter(filters[0].(map[string]interface{}))
 		}
 		
-		return tc
+		if len(ts) > 0 {
+			dataSchema["transformSpec"] = ts
+		}
 	}
 	
-	return nil
-}
\ No newline at end of file
+	return dataSchema
+}
+
+func buildIOConfig(d *schema.ResourceData) map[string]interface{} {
+	ioConfig := map[string]interface{}{}
+	
+	// Topic or topic pattern
+	if topic := d.Get("topic").(string); topic != "" {
+		ioConfig["topic"] = topic
+	}
+	if topicPattern := d.Get("topic_pattern").(string); topicPattern != "" {
+		ioConfig["topicPattern"] = topicPattern
+	}
+	
+	// Input format
+	if inputFormats := d.Get("input_format").([]interface{}); len(inputFormats) > 0 {
+		ioConfig["inputFormat"] = buildInputFormat(inputFormats[0].(map[string]interface{}))
+	}
+	
+	// Consumer properties
+	if consumerProps := d.Get("consumer_properties").(map[string]interface{}); len(consumerProps) > 0 {
+		ioConfig["consumerProperties"] = consumerProps
+	}
+	
+	// Task configuration
+	ioConfig["taskCount"] = d.Get("task_count").(int)
+	ioConfig["replicas"] = d.Get("replicas").(int)
+	ioConfig["taskDuration"] = d.Get("task_duration").(string)
+	ioConfig["useEarliestOffset"] = d.Get("use_earliest_offset").(bool)
+	ioConfig["completionTimeout"] = d.Get("completion_timeout").(string)
+	
+	// Optional timing, left to Druid's defaults unless set
+	optionalStrings := map[string]string{
+		"start_delay":                            "startDelay",
+		"period":                                 "period",
+		"late_message_rejection_period":          "lateMessageRejectionPeriod",
+		"late_message_rejection_start_date_time": "lateMessageRejectionStartDateTime",
+		"early_message_rejection_period":         "earlyMessageRejectionPeriod",
+	}
+	for key, druidKey := range optionalStrings {
+		if v := d.Get(key).(string); v != "" {
+			ioConfig[druidKey] = v
+		}
+	}
+	if pollTimeout := d.Get("poll_timeout").(int); pollTimeout > 0 {
+		ioConfig["pollTimeout"] = pollTimeout
+	}
+	
+	if autoScalerConfig := firstBlock(d.Get("autoscaler_config")); autoScalerConfig != nil {
+		ioConfig["autoScalerConfig"] = buildAutoScalerConfig(autoScalerConfig)
+	}
+	
+	// Idle configuration
+	if idleConfigs := d.Get("idle_config").([]interface{}); len(idleConfigs) > 0 {
+		idleConfig := idleConfigs[0].(map[string]interface{})
+		ic := map[string]interface{}{
+			"enabled": idleConfig["enabled"].(bool),
+		}
+		if inactiveAfter := idleConfig["inactive_after_millis"].(int); inactiveAfter > 0 {
+			ic["inactiveAfterMillis"] = inactiveAfter
+		}
+		ioConfig["idleConfig"] = ic
+	}
+	
+	return ioConfig
+}
+
+// tuningConfigIntFields, tuningConfigBoolFields and tuningConfigStringFields
+// map the scalar tuning_config attributes to their Druid keys.
+var tuningConfigIntFields = map[string]string{
+	"max_rows_per_segment":       "maxRowsPerSegment",
+	"max_rows_in_memory":         "maxRowsInMemory",
+	"max_bytes_in_memory":        "maxBytesInMemory",
+	"max_pending_persists":       "maxPendingPersists",
+	"max_parse_exceptions":       "maxParseExceptions",
+	"max_saved_parse_exceptions": "maxSavedParseExceptions",
+	"worker_threads":             "workerThreads",
+	"chat_threads":               "chatThreads",
+	"chat_retries":               "chatRetries",
+	"max_total_rows":             "maxTotalRows",
+	"max_columns_to_merge":       "maxColumnsToMerge",
+	"handoff_condition_timeout":  "handoffConditionTimeout",
+	"max_records_per_poll":       "maxRecordsPerPoll",
+}
+
+var tuningConfigBoolFields = map[string]string{
+	"skip_bytes_in_memory_overhead_check": "skipBytesInMemoryOverheadCheck",
+	"log_parse_exceptions":                "logParseExceptions",
+	"reset_offset_automatically":          "resetOffsetAutomatically",
+	"report_parse_exceptions":             "reportParseExceptions",
+}
+
+var tuningConfigStringFields = map[string]string{
+	"intermediate_persist_period": "intermediatePersistPeriod",
+	"http_timeout":                "httpTimeout",
+	"shutdown_timeout":            "shutdownTimeout",
+	"offset_fetch_period":         "offsetFetchPeriod",
+}
+
+// tuningConfigDefaults are the values Druid reports for unset options.
+var tuningConfigDefaults = map[string]interface{}{
+	"maxRowsPerSegment":         5000000,
+	"maxRowsInMemory":           150000,
+	"maxParseExceptions":        2147483647,
+	"chatRetries":               8,
+	"maxColumnsToMerge":         -1,
+	"handoffConditionTimeout":   900000,
+	"intermediatePersistPeriod": "PT10M",
+	"httpTimeout":               "PT10S",
+	"shutdownTimeout":           "PT80S",
+	"offsetFetchPeriod":         "PT30S",
+}
+
+func buildTuningConfig(d *schema.ResourceData) map[string]interface{} {
+	configured := func(key string) bool {
+		return blockAttrConfigured(d.GetRawConfig(), "tuning_config", key)
+	}
+	return buildTuningConfigBlock(firstBlock(d.Get("tuning_config")), configured)
+}
+
+// buildTuningConfigBlock converts a tuning_config block into a Druid
+// tuningConfig. Only options set by the user are emitted, so Druid's own
+// defaults apply to the rest. configured reports whether an option is set
+// explicitly, which lets zero and false values through; options with other
+// values are always emitted.
+func buildTuningConfigBlock(tuningConfig map[string]interface{}, configured func(key string) bool) map[string]interface{} {
+	if tuningConfig == nil {
+		return nil
+	}
+	
+	tc := map[string]interface{}{
+		"type": "kafka",
+	}
+	
+	for _, fields := range []map[string]string{tuningConfigIntFields, tuningConfigBoolFields, tuningConfigStringFields} {
+		for key, druidKey := range fields {
+			if v := tuningConfig[key]; isSet(v) || configured(key) {
+				tc[druidKey] = v
+			}
+		}
+	}
+	
+	// The partitions spec supersedes the top-level row limits, which are
+	// mirrored for Druid versions that only read those.
+	if partitionsSpec := firstBlock(tuningConfig["partitions_spec"]); partitionsSpec != nil {
+		ps := map[string]interface{}{
+			"type": partitionsSpec["type"].(string),
+		}
+		if maxRows := partitionsSpec["max_rows_per_segment"].(int); maxRows > 0 {
+			ps["maxRowsPerSegment"] = maxRows
+			tc["maxRowsPerSegment"] = maxRows
+		}
+		if maxTotalRows := partitionsSpec["max_total_rows"].(int); maxTotalRows > 0 {
+			ps["maxTotalRows"] = maxTotalRows
+			tc["maxTotalRows"] = maxTotalRows
+		}
+		tc["partitionsSpec"] = ps
+	}
+	
+	// Index specs
+	if indexSpec := firstBlock(tuningConfig["index_spec"]); indexSpec != nil {
+		if is := buildIndexSpec(indexSpec); is != nil {
+			tc["indexSpec"] = is
+		}
+	}
+	if indexSpec := firstBlock(tuningConfig["index_spec_for_intermediate_persists"]); indexSpec != nil {
+		if is := buildIndexSpec(indexSpec); is != nil {
+			tc["indexSpecForIntermediatePersists"] = is
+		}
+	}
+	
+	if appendableIndexSpec := firstBlock(tuningConfig["appendable_index_spec"]); appendableIndexSpec != nil {
+		ais := map[string]interface{}{
+			"type": appendableIndexSpec["type"].(string),
+		}
+		if preserve := appendableIndexSpec["preserve_existing_metrics"].(bool); preserve {
+			ais["preserveExistingMetrics"] = preserve
+		}
+		tc["appendableIndexSpec"] = ais
+	}
+	
+	if segmentWriteOut := tuningConfig["segment_write_out_medium_factory"].(map[string]interface{}); len(segmentWriteOut) > 0 {
+		tc["segmentWriteOutMediumFactory"] = segmentWriteOut
+	}
+	
+	return tc
+}
+
+// flattenSupervisorSpec reads a supervisor spec returned by Druid back into
+// the resource data. Blocks that Druid always fills with server defaults are
+// only read back when they are already managed, unless importing. Keys
+// overridden by spec_json are not read back.
+func flattenSupervisorSpec(d *schema.ResourceData, supervisor map[string]interface{}, importing bool) error {
+	supervisor, err := withoutSpecJSONOverrides(d, supervisor)
+	if err != nil {
+		return err
+	}
+	
+	ingestionSpec := supervisor
+	if s, ok := supervisor["spec"].(map[string]interface{}); ok {
+		ingestionSpec = s
+	}
+	
+	dataSchema, _ := ingestionSpec["dataSchema"].(map[string]interface{})
+	ioConfig, _ := ingestionSpec["ioConfig"].(map[string]interface{})
+	tuningConfig, _ := ingestionSpec["tuningConfig"].(map[string]interface{})
+	
+	values := map[string]interface{}{}
+	
+	if dataSchema != nil {
+		values["datasource"] = flattenString(dataSchema["dataSource"])
+		
+		timestampColumn := ""
+		if ts, ok := dataSchema["timestampSpec"].(map[string]interface{}); ok {
+			values["timestamp_spec"] = flattenTimestampSpec(ts)
+			timestampColumn = flattenString(ts["column"])
+		}
+		
+		metrics, _ := dataSchema["metricsSpec"].([]interface{})
+		values["metrics_spec"] = flattenMetricsSpec(metrics, d.Get("metrics_spec").([]interface{}))
+		
+		// Druid adds the timestamp column and metric names to dimensionExclusions
+		// on its own, so they are only kept when the configuration lists them.
+		autoExclusions := map[string]bool{timestampColumn: true}
+		for _, metric := range metrics {
+			if m, ok := metric.(map[string]interface{}); ok {
+				autoExclusions[flattenString(m["name"])] = true
+			}
+		}
+		if ds, ok := dataSchema["dimensionsSpec"].(map[string]interface{}); ok {
+			values["dimensions_spec"] = flattenDimensionsSpec(ds, d.Get("dimensions_spec").([]interface{}), autoExclusions)
+		}
+		
+		transformSpec, _ := dataSchema["transformSpec"].(map[string]interface{})
+		if ts := flattenTransformSpec(transformSpec); len(ts) > 0 || len(d.Get("transform_spec").([]interface{})) > 0 {
+			values["transform_spec"] = ts
+		}
+		
+		// Druid always returns a granularitySpec filled with server defaults, so
+		// it is only tracked once the configuration manages it.
+		if gs, ok := dataSchema["granularitySpec"].(map[string]interface{}); ok && (importing || len(d.Get("granularity_spec").([]interface{})) > 0) {
+			values["granularity_spec"] = flattenGranularitySpec(gs, firstBlock(d.Get("granularity_spec")))
+		}
+	}
+	
+	if ioConfig != nil {
+		values["topic"] = flattenString(ioConfig["topic"])
+		values["topic_pattern"] = flattenString(ioConfig["topicPattern"])
+		
+		if inputFormat, ok := ioConfig["inputFormat"].(map[string]interface{}); ok {
+			values["input_format"] = flattenInputFormat(inputFormat, firstBlock(d.Get("input_format")), true)
+		}
+		
+		values["consumer_properties"] = flattenStringMap(ioConfig["consumerProperties"])
+		
+		if v, ok := ioConfig["taskCount"]; ok {
+			values["task_count"] = flattenInt(v)
+		}
+		if v, ok := ioConfig["replicas"]; ok {
+			values["replicas"] = flattenInt(v)
+		}
+		if v, ok := ioConfig["taskDuration"]; ok {
+			values["task_duration"] = flattenString(v)
+		}
+		if v, ok := ioConfig["useEarliestOffset"]; ok {
+			values["use_earliest_offset"] = flattenBool(v)
+		}
+		if v, ok := ioConfig["completionTimeout"]; ok {
+			values["completion_timeout"] = flattenString(v)
+		}
+		
+		// Druid reports its defaults for these, which are dropped unless configured.
+		values["start_delay"] = flattenDefaulted(ioConfig["startDelay"], "PT5S", d.Get("start_delay"))
+		values["period"] = flattenDefaulted(ioConfig["period"], "PT30S", d.Get("period"))
+		values["poll_timeout"] = flattenDefaultedInt(ioConfig["pollTimeout"], 100, d.Get("poll_timeout"))
+		values["late_message_rejection_period"] = flattenString(ioConfig["lateMessageRejectionPeriod"])
+		values["late_message_rejection_start_date_time"] = flattenString(ioConfig["lateMessageRejectionStartDateTime"])
+		values["early_message_rejection_period"] = flattenString(ioConfig["earlyMessageRejectionPeriod"])
+		
+		if ac, ok := ioConfig["autoScalerConfig"].(map[string]interface{}); ok {
+			values["autoscaler_config"] = flattenAutoScalerConfig(ac, firstBlock(d.Get("autoscaler_config")))
+		} else {
+			values["autoscaler_config"] = []interface{}{}
+		}
+		
+		if ic, ok := ioConfig["idleConfig"].(map[string]interface{}); ok {
+			values["idle_config"] = []interface{}{
+				map[string]interface{}{
+					"enabled":               flattenBool(ic["enabled"]),
+					"inactive_after_millis": flattenInt(ic["inactiveAfterMillis"]),
+				},
+			}
+		} else {
+			values["idle_config"] = []interface{}{}
+		}
+	}
+	
+	// Like the granularitySpec, Druid fills in a complete tuningConfig.
+	if tuningConfig != nil && (importing || len(d.Get("tuning_config").([]interface{})) > 0) {
+		values["tuning_config"] = flattenTuningConfig(tuningConfig, firstBlock(d.Get("tuning_config")), importing)
+	}
+	
+	context, ok := ingestionSpec["context"]
+	if !ok {
+		context = supervisor["context"]
+	}
+	values["context"] = flattenStringMap(context)
+	
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	
+	return nil
+}
+
+func flattenSupervisorStatus(d *schema.ResourceData, status *SupervisorStatus) error {
+	minimumLag := map[string]interface{}{}
+	for partition, lag := range status.MinimumLag {
+		minimumLag[partition] = int(lag)
+	}
+	
+	recentErrors := []interface{}{}
+	for _, e := range status.RecentErrors {
+		recentErrors = append(recentErrors, map[string]interface{}{
+			"timestamp":        e.Timestamp,
+			"exception_class":  e.ExceptionClass,
+			"message":          e.Message,
+			"stream_exception": e.StreamException,
+		})
+	}
+	
+	values := map[string]interface{}{
+		"detailed_state":       status.DetailedState,
+		"healthy":              status.Healthy,
+		"partitions":           status.Partitions,
+		"active_tasks":         flattenSupervisorTasks(status.ActiveTasks),
+		"publishing_tasks":     flattenSupervisorTasks(status.PublishingTasks),
+		"aggregate_lag":        int(status.AggregateLag),
+		"minimum_lag":          minimumLag,
+		"offsets_last_updated": status.OffsetsLastUpdated,
+		"recent_errors":        recentErrors,
+	}
+	
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	
+	return nil
+}
+
+func flattenSupervisorTasks(tasks []SupervisorTask) []interface{} {
+	result := []interface{}{}
+	for _, task := range tasks {
+		result = append(result, map[string]interface{}{
+			"id":                task.ID,
+			"start_time":        task.StartTime,
+			"remaining_seconds": int(task.RemainingSeconds),
+		})
+	}
+	return result
+}
+
+func flattenTimestampSpec(ts map[string]interface{}) []interface{} {
+	format := flattenString(ts["format"])
+	if format == "" {
+		format = "auto"
+	}
+	
+	return []interface{}{
+		map[string]interface{}{
+			"column":        flattenString(ts["column"]),
+			"format":        format,
+			"missing_value": flattenString(ts["missingValue"]),
+		},
+	}
+}
+
+func flattenDimensionsSpec(ds map[string]interface{}, prior []interface{}, autoExclusions map[string]bool) []interface{} {
+	var priorDims, priorExclusions []interface{}
+	if len(prior) > 0 && prior[0] != nil {
+		priorSpec := prior[0].(map[string]interface{})
+		priorDims, _ = priorSpec["dimensions"].([]interface{})
+		priorExclusions, _ = priorSpec["dimension_exclusions"].([]interface{})
+	}
+	
+	var dimensions []interface{}
+	if dims, ok := ds["dimensions"].([]interface{}); ok {
+		for i, dim := range dims {
+			// Dimensions may be submitted as plain column names
+			if name, ok := dim.(string); ok {
+				dimensions = append(dimensions, map[string]interface{}{
+					"name":                 name,
+					"type":                 "string",
+					"multi_value_handling": "",
+					"create_bitmap_index":  true,
+				})
+				continue
+			}
+			
+			dimMap, ok := dim.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			
+			dimType := flattenString(dimMap["type"])
+			if dimType == "" {
+				dimType = "string"
+			}
+			
+			// Druid reports the default SORTED_ARRAY handling for every string
+			// dimension; keep it only when it was explicitly configured.
+			mvh := flattenString(dimMap["multiValueHandling"])
+			if strings.EqualFold(mvh, "sorted_array") {
+				priorMvh := ""
+				if i < len(priorDims) && priorDims[i] != nil {
+					priorMvh = priorDims[i].(map[string]interface{})["multi_value_handling"].(string)
+				}
+				if priorMvh == "" {
+					mvh = ""
+				}
+			}
+			
+			// Only string dimensions have a bitmap index; Druid reports false
+			// for the other types.
+			createBitmapIndex := true
+			if v, ok := dimMap["createBitmapIndex"]; ok && v != nil && dimType == "string" {
+				createBitmapIndex = flattenBool(v)
+			}
+			
+			dimensions = append(dimensions, map[string]interface{}{
+				"name":                 flattenString(dimMap["name"]),
+				"type":                 dimType,
+				"multi_value_handling": mvh,
+				"create_bitmap_index":  createBitmapIndex,
+			})
+		}
+	}
+	
+	configuredExclusions := map[string]bool{}
+	for _, exclusion := range priorExclusions {
+		configuredExclusions[exclusion.(string)] = true
+	}
+	
+	var exclusions []interface{}
+	if excl, ok := ds["dimensionExclusions"].([]interface{}); ok {
+		for _, exclusion := range excl {
+			name := flattenString(exclusion)
+			if autoExclusions[name] && !configuredExclusions[name] {
+				continue
+			}
+			exclusions = append(exclusions, name)
+		}
+	}
+	
+	var spatialDimensions []interface{}
+	if spatialDims, ok := ds["spatialDimensions"].([]interface{}); ok {
+		for _, spatial := range spatialDims {
+			spatialMap, ok := spatial.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			spatialDimensions = append(spatialDimensions, map[string]interface{}{
+				"dim_name": flattenString(spatialMap["dimName"]),
+				"dims":     flattenStringList(spatialMap["dims"]),
+			})
+		}
+	}
+	
+	useSchemaDiscovery := flattenBool(ds["useSchemaDiscovery"])
+	includeAllDimensions := flattenBool(ds["includeAllDimensions"])
+	
+	if len(dimensions) == 0 && len(exclusions) == 0 && len(spatialDimensions) == 0 && !useSchemaDiscovery && !includeAllDimensions && len(prior) == 0 {
+		return []interface{}{}
+	}
+	
+	return []interface{}{
+		map[string]interface{}{
+			"dimensions":             dimensions,
+			"dimension_exclusions":   exclusions,
+			"spatial_dimensions":     spatialDimensions,
+			"use_schema_discovery":   useSchemaDiscovery,
+			"include_all_dimensions": includeAllDimensions,
+		},
+	}
+}
+
+func flattenMetricsSpec(metrics []interface{}, prior []interface{}) []interface{} {
+	// Match prior metrics by name so that reordering doesn't lose settings
+	priorByName := map[string]map[string]interface{}{}
+	for _, p := range prior {
+		if m, ok := p.(map[string]interface{}); ok {
+			priorByName[flattenString(m["name"])] = m
+		}
+	}
+	
+	result := []interface{}{}
+	for _, metric := range metrics {
+		metricMap, ok := metric.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result = append(result, flattenAggregator(metricMap, priorByName[flattenString(metricMap["name"])], false))
+	}
+	return result
+}
+
+// flattenTransformSpec returns an empty list when the spec has neither
+// transforms nor a filter, which is how Druid reports an unset transformSpec.
+func flattenTransformSpec(ts map[string]interface{}) []interface{} {
+	transforms := []interface{}{}
+	if list, ok := ts["transforms"].([]interface{}); ok {
+		for _, transform := range list {
+			if t, ok := transform.(map[string]interface{}); ok {
+				transforms = append(transforms, map[string]interface{}{
+					"type":       flattenString(t["type"]),
+					"name":       flattenString(t["name"]),
+					"expression": flattenString(t["expression"]),
+				})
+			}
+		}
+	}
+	
+	filter := []interface{}{}
+	if f, ok := ts["filter"].(map[string]interface{}); ok {
+		filter = append(filter, flattenFilter(f, maxFilterDepth))
+	}
+	
+	if len(transforms) == 0 && len(filter) == 0 {
+		return []interface{}{}
+	}
+	
+	return []interface{}{
+		map[string]interface{}{
+			"transforms": transforms,
+			"filter":     filter,
+		},
+	}
+}
+
+func flattenGranularitySpec(gs map[string]interface{}, prior map[string]interface{}) []interface{} {
+	gsType := flattenString(gs["type"])
+	if gsType == "" {
+		gsType = "uniform"
+	}
+	
+	rollup := true
+	if v, ok := gs["rollup"]; ok && v != nil {
+		rollup = flattenBool(v)
+	}
+	
+	segmentGranularity, segmentGranularityPeriod := flattenGranularityValue(gs["segmentGranularity"], prior["segment_granularity"], prior["segment_granularity_period"], "HOUR")
+	queryGranularity, queryGranularityPeriod := flattenGranularityValue(gs["queryGranularity"], prior["query_granularity"], prior["query_granularity_period"], "NONE")
+	
+	return []interface{}{
+		map[string]interface{}{
+			"type":                       gsType,
+			"segment_granularity":        segmentGranularity,
+			"segment_granularity_period": segmentGranularityPeriod,
+			"query_granularity":          queryGranularity,
+			"query_granularity_period":   queryGranularityPeriod,
+			"intervals":                  flattenStringList(gs["intervals"]),
+			"rollup":                     rollup,
+		},
+	}
+}
+
+// flattenGranularity converts a granularity that Druid may return either as a
+// plain name or as an object such as {"type": "none"} back into its name.
+func flattenGranularity(v interface{}) string {
+	if g, ok := v.(map[string]interface{}); ok {
+		return strings.ToUpper(flattenString(g["type"]))
+	}
+	return flattenString(v)
+}
+
+// flattenTuningConfig converts a Druid tuningConfig back into a tuning_config
+// block. Blocks Druid always reports are only read back when prior manages
+// them or when importing.
+func flattenTuningConfig(tc map[string]interface{}, prior map[string]interface{}, importing bool) []interface{} {
+	result := map[string]interface{}{}
+	
+	// Druid reports every option, so its defaults are dropped unless prior
+	// sets them.
+	for key, druidKey := range tuningConfigIntFields {
+		defaultValue, _ := tuningConfigDefaults[druidKey].(int)
+		result[key] = flattenDefaultedInt(tc[druidKey], defaultValue, prior[key])
+	}
+	for key, druidKey := range tuningConfigBoolFields {
+		result[key] = flattenBool(tc[druidKey])
+	}
+	for key, druidKey := range tuningConfigStringFields {
+		defaultValue, _ := tuningConfigDefaults[druidKey].(string)
+		result[key] = flattenDefaulted(tc[druidKey], defaultValue, prior[key])
+	}
+	
+	// A managed partitions spec takes over the top-level row limits, which
+	// keep their prior values.
+	result["partitions_spec"] = []interface{}{}
+	if isSet(prior["partitions_spec"]) {
+		ps, ok := tc["partitionsSpec"].(map[string]interface{})
+		if !ok {
+			ps = tc
+		}
+		result["partitions_spec"] = []interface{}{
+			map[string]interface{}{
+				"type":                 "dynamic",
+				"max_rows_per_segment": flattenInt(ps["maxRowsPerSegment"]),
+				"max_total_rows":       flattenInt(ps["maxTotalRows"]),
+			},
+		}
+		result["max_rows_per_segment"] = prior["max_rows_per_segment"]
+		result["max_total_rows"] = prior["max_total_rows"]
+	}
+	
+	// Druid always reports index specs, so only track them when configured,
+	// or on import when they differ from Druid's defaults
+	for key, druidKey := range map[string]string{
+		"index_spec":                           "indexSpec",
+		"index_spec_for_intermediate_persists": "indexSpecForIntermediatePersists",
+	} {
+		if is, ok := tc[druidKey].(map[string]interface{}); ok {
+			indexSpec := flattenIndexSpec(is, firstBlock(prior[key]))
+			if isSet(prior[key]) || (importing && !isDefaultIndexSpec(indexSpec)) {
+				result[key] = indexSpec
+			}
+		}
+	}
+	
+	// Druid reports the default onheap index as well
+	if ais, ok := tc["appendableIndexSpec"].(map[string]interface{}); ok {
+		aisType := flattenString(ais["type"])
+		preserve := flattenBool(ais["preserveExistingMetrics"])
+		if isSet(prior["appendable_index_spec"]) || (aisType != "" && aisType != "onheap") || preserve {
+			result["appendable_index_spec"] = []interface{}{
+				map[string]interface{}{
+					"type":                      aisType,
+					"preserve_existing_metrics": preserve,
+				},
+			}
+		}
+	}
+	
+	if swo, ok := tc["segmentWriteOutMediumFactory"].(map[string]interface{}); ok {
+		result["segment_write_out_medium_factory"] = flattenStringMap(swo)
+	}
+	
+	return []interface{}{result}
+}
+
+func flattenString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func flattenInt(v interface{}) int {
+	switch value := v.(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case string:
+		i, _ := strconv.Atoi(value)
+		return i
+	default:
+		return 0
+	}
+}
+
+func flattenFloat(v interface{}) float64 {
+	switch value := v.(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	case string:
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+func flattenBool(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		b, _ := strconv.ParseBool(value)
+		return b
+	default:
+		return false
+	}
+}
+
+// flattenDefaulted returns v unless it is the default Druid fills in and the
+// prior value was not set, in which case the attribute is left empty.
+func flattenDefaulted(v interface{}, defaultValue string, prior interface{}) string {
+	value := flattenString(v)
+	if value == defaultValue && !isSet(prior) {
+		return ""
+	}
+	return value
+}
+
+// flattenDefaultedInt is flattenDefaulted for integer attributes.
+func flattenDefaultedInt(v interface{}, defaultValue int, prior interface{}) int {
+	value := flattenInt(v)
+	if value == defaultValue && !isSet(prior) {
+		return 0
+	}
+	return value
+}
+
+// flattenDefaultedFloat is flattenDefaulted for float attributes.
+func flattenDefaultedFloat(v interface{}, defaultValue float64, prior interface{}) float64 {
+	value := flattenFloat(v)
+	if value == defaultValue && !isSet(prior) {
+		return 0
+	}
+	return value
+}
+
+// isSet reports whether a value read from the resource data differs from its
+// zero value.
+func isSet(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return false
+	case string:
+		return value != ""
+	case int:
+		return value != 0
+	case float64:
+		return value != 0
+	case bool:
+		return value
+	case []interface{}:
+		return len(value) > 0
+	case map[string]interface{}:
+		return len(value) > 0
+	case *schema.Set:
+		return value.Len() > 0
+	}
+	return true
+}
+
+// sortedKeys returns the keys of a map in sorted order, so that validation
+// errors are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func flattenStringMap(v interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	if m, ok := v.(map[string]interface{}); ok {
+		for key, value := range m {
+			if value == nil {
+				continue
+			}
+			result[key] = flattenString(value)
+		}
+	}
+	return result
+}
+
+// firstBlock returns the single element of a MaxItems: 1 block, or nil when
+// the block is not set.
+func firstBlock(v interface{}) map[string]interface{} {
+	if list, ok := v.([]interface{}); ok && len(list) > 0 {
+		if block, ok := list[0].(map[string]interface{}); ok {
+			return block
+		}
+	}
+	return nil
+}
+
+func flattenStringList(v interface{}) []interface{} {
+	result := []interface{}{}
+	if l, ok := v.([]interface{}); ok {
+		for _, value := range l {
+			result = append(result, flattenString(value))
+		}
+	}
+	return result
+}
+
+// suppressEquivalentJSON ignores differences in key ordering and whitespace
+// between two JSON documents.
+func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal([]byte(old), &oldValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newValue); err != nil {
+		return false
+	}
+	
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+// suppressCaseDifferences ignores case-only differences for enum values that
+// Druid reports in upper case.
+func suppressCaseDifferences(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// suppressEquivalentDurations ignores differences between ISO 8601 durations
+// that describe the same length of time, since Druid normalizes values such
+// as PT1H to PT3600S when it returns a spec.
+func suppressEquivalentDurations(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	
+	oldDuration, err := parseISO8601Duration(old)
+	if err != nil {
+		return false
+	}
+	newDuration, err := parseISO8601Duration(new)
+	if err != nil {
+		return false
+	}
+	
+	return oldDuration == newDuration
+}
+
+var iso8601DurationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseISO8601Duration parses the fixed-length subset of ISO 8601 durations
+// (weeks, days, hours, minutes and seconds) that Druid accepts for durations.
+func parseISO8601Duration(value string) (time.Duration, error) {
+	matches := iso8601DurationPattern.FindStringSubmatch(value)
+	if matches == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("%q is not a valid ISO 8601 duration", value)
+	}
+	
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute}
+	var duration time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(matches[i+1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid ISO 8601 duration: %w", value, err)
+		}
+		duration += time.Duration(n) * unit
+	}
+	
+	if matches[5] != "" {
+		seconds, err := strconv.ParseFloat(matches[5], 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid ISO 8601 duration: %w", value, err)
+		}
+		duration += time.Duration(seconds * float64(time.Second))
+	}
+	
+	return duration, nil
+}
+
+var iso8601PeriodPattern = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// iso8601DateTimeLayouts are the ISO 8601 timestamp forms accepted wherever
+// Druid expects a date time. Timestamps without an offset are in UTC.
+var iso8601DateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02T15Z07:00",
+	"2006-01-02T15",
+	"2006-01-02",
+}
+
+// parseISO8601Period parses an ISO 8601 period into its calendar part
+// (years, months and days) and its fixed-length part.
+func parseISO8601Period(value string) (years, months, days int, duration time.Duration, err error) {
+	matches := iso8601PeriodPattern.FindStringSubmatch(value)
+	if matches == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, 0, 0, 0, fmt.Errorf("%q is not a valid ISO 8601 period", value)
+	}
+	
+	n := make([]int, 6)
+	for i := range n {
+		if matches[i+1] == "" {
+			continue
+		}
+		if n[i], err = strconv.Atoi(matches[i+1]); err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("%q is not a valid ISO 8601 period: %w", value, err)
+		}
+	}
+	duration = time.Duration(n[4])*time.Hour + time.Duration(n[5])*time.Minute
+	if matches[7] != "" {
+		seconds, err := strconv.ParseFloat(matches[7], 64)
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("%q is not a valid ISO 8601 period: %w", value, err)
+		}
+		duration += time.Duration(seconds * float64(time.Second))
+	}
+	
+	return n[0], n[1], 7*n[2] + n[3], duration, nil
+}
+
+// parseISO8601DateTime parses an ISO 8601 timestamp.
+func parseISO8601DateTime(value string) (time.Time, error) {
+	for _, layout := range iso8601DateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 date time", value)
+}
+
+// parseISO8601Interval parses an ISO 8601 interval given as start/end,
+// start/period or period/end.
+func parseISO8601Interval(value string) (time.Time, time.Time, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval", value)
+	}
+	
+	if strings.HasPrefix(parts[0], "P") {
+		end, err := parseISO8601DateTime(parts[1])
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval: %w", value, err)
+		}
+		years, months, days, duration, err := parseISO8601Period(parts[0])
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval: %w", value, err)
+		}
+		return end.Add(-duration).AddDate(-years, -months, -days), end, nil
+	}
+	
+	start, err := parseISO8601DateTime(parts[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval: %w", value, err)
+	}
+	if strings.HasPrefix(parts[1], "P") {
+		years, months, days, duration, err := parseISO8601Period(parts[1])
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval: %w", value, err)
+		}
+		return start, start.AddDate(years, months, days).Add(duration), nil
+	}
+	end, err := parseISO8601DateTime(parts[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval: %w", value, err)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 interval: end is before start", value)
+	}
+	return start, end, nil
+}
+
+func validateISO8601Period(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, _, _, _, err := parseISO8601Period(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+func validateISO8601Duration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseISO8601Duration(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+func validateISO8601DateTime(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseISO8601DateTime(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+func validateISO8601Interval(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, _, err := parseISO8601Interval(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentIntervals ignores differences between intervals that
+// cover the same time range, since Druid returns intervals with full
+// millisecond timestamps in UTC.
+func suppressEquivalentIntervals(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	
+	oldStart, oldEnd, err := parseISO8601Interval(old)
+	if err != nil {
+		return false
+	}
+	newStart, newEnd, err := parseISO8601Interval(new)
+	if err != nil {
+		return false
+	}
+	
+	return oldStart.Equal(newStart) && oldEnd.Equal(newEnd)
+}
+
+// suppressEquivalentDateTimes ignores differences between timestamps that
+// denote the same instant, since Druid returns them in UTC with milliseconds.
+func suppressEquivalentDateTimes(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	
+	oldTime, err := parseISO8601DateTime(old)
+	if err != nil {
+		return false
+	}
+	newTime, err := parseISO8601DateTime(new)
+	if err != nil {
+		return false
+	}
+	
+	return oldTime.Equal(newTime)
+}
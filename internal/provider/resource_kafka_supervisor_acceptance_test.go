@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -95,6 +96,196 @@ func TestAccKafkaSupervisor_advanced(t *testing.T) {
 	})
 }
 
+func TestAccKafkaSupervisor_import(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasource := acctest.RandomWithPrefix("test-datasource")
+	
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(mockServer.URL()),
+		CheckDestroy:      testAccCheckKafkaSupervisorDestroy(mockServer),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaSupervisorConfig_advanced(mockServer.URL(), datasource),
+			},
+			{
+				ResourceName:      "druid_kafka_supervisor.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccKafkaSupervisor_drift(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasource := acctest.RandomWithPrefix("test-datasource")
+	
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(mockServer.URL()),
+		CheckDestroy:      testAccCheckKafkaSupervisorDestroy(mockServer),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaSupervisorConfig_basic(mockServer.URL(), datasource),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKafkaSupervisorExists("druid_kafka_supervisor.test", mockServer),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "task_count", "1"),
+				),
+			},
+			{
+				// Simulate an edit made in the Druid console
+				PreConfig: func() {
+					supervisorID := datasource + "-supervisor"
+					spec := mockServer.GetSupervisorSpec(supervisorID)
+					spec["spec"].(map[string]interface{})["ioConfig"].(map[string]interface{})["taskCount"] = 4
+					mockServer.SetSupervisorSpec(supervisorID, spec)
+				},
+				Config:             testAccKafkaSupervisorConfig_basic(mockServer.URL(), datasource),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccKafkaSupervisorConfig_basic(mockServer.URL(), datasource),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "task_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKafkaSupervisor_suspend(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasource := acctest.RandomWithPrefix("test-datasource")
+	supervisorID := datasource + "-supervisor"
+	
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(mockServer.URL()),
+		CheckDestroy:      testAccCheckKafkaSupervisorDestroy(mockServer),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaSupervisorConfig_suspended(mockServer.URL(), datasource, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "suspended", "false"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "state", "RUNNING"),
+				),
+			},
+			{
+				Config: testAccKafkaSupervisorConfig_suspended(mockServer.URL(), datasource, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "suspended", "true"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "state", "SUSPENDED"),
+					testAccCheckKafkaSupervisorSubmissions(mockServer, supervisorID, 1),
+				),
+			},
+			{
+				Config: testAccKafkaSupervisorConfig_suspended(mockServer.URL(), datasource, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "suspended", "false"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "state", "RUNNING"),
+					testAccCheckKafkaSupervisorSubmissions(mockServer, supervisorID, 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKafkaSupervisor_replace(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasource := acctest.RandomWithPrefix("test-datasource")
+	renamed := acctest.RandomWithPrefix("test-datasource")
+	
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(mockServer.URL()),
+		CheckDestroy:      testAccCheckKafkaSupervisorDestroy(mockServer),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaSupervisorConfig_basic(mockServer.URL(), datasource),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKafkaSupervisorExists("druid_kafka_supervisor.test", mockServer),
+				),
+			},
+			{
+				Config: testAccKafkaSupervisorConfig_basic(mockServer.URL(), renamed),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKafkaSupervisorExists("druid_kafka_supervisor.test", mockServer),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "datasource", renamed),
+					func(s *terraform.State) error {
+						if mockServer.GetSupervisor(datasource+"-supervisor") != nil {
+							return fmt.Errorf("supervisor for %s still exists after replacement", datasource)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccKafkaSupervisor_supervisorID(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasource := acctest.RandomWithPrefix("test-datasource")
+	
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(mockServer.URL()),
+		CheckDestroy:      testAccCheckKafkaSupervisorDestroy(mockServer),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKafkaSupervisorConfig_supervisorID(mockServer.URL(), datasource),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKafkaSupervisorExists("druid_kafka_supervisor.eu", mockServer),
+					testAccCheckKafkaSupervisorExists("druid_kafka_supervisor.us", mockServer),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.eu", "id", datasource+"-eu"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.us", "id", datasource+"-us"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.eu", "supervisor_id", datasource+"-eu"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKafkaSupervisor_transformSpec(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasource := acctest.RandomWithPrefix("test-datasource")
+	
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders(mockServer.URL()),
+		CheckDestroy:      testAccCheckKafkaSupervisorDestroy(mockServer),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccKafkaSupervisorConfig_transformSpec(mockServer.URL(), datasource, ""),
+				ExpectError: regexp.MustCompile("selector filter requires dimension"),
+			},
+			{
+				Config: testAccKafkaSupervisorConfig_transformSpec(mockServer.URL(), datasource, "event_type"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKafkaSupervisorExists("druid_kafka_supervisor.test", mockServer),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "transform_spec.0.transforms.0.name", "country_upper"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "transform_spec.0.filter.0.type", "and"),
+					resource.TestCheckResourceAttr("druid_kafka_supervisor.test", "transform_spec.0.filter.0.fields.0.dimension", "event_type"),
+				),
+			},
+		},
+	})
+}
+
 func testAccPreCheck(t *testing.T) {
 	// Add any pre-check logic here
 }
@@ -147,6 +338,15 @@ func testAccCheckKafkaSupervisorDestroy(mockServer *MockDruidServer) resource.Te
 	}
 }
 
+func testAccCheckKafkaSupervisorSubmissions(mockServer *MockDruidServer, supervisorID string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if count := mockServer.SubmissionCount(supervisorID); count != expected {
+			return fmt.Errorf("expected %d spec submissions for %s, got %d", expected, supervisorID, count)
+		}
+		return nil
+	}
+}
+
 func testAccKafkaSupervisorConfig_basic(endpoint, datasource string) string {
 	return fmt.Sprintf(`
 provider "druid" {
@@ -269,4 +469,130 @@ resource "druid_kafka_supervisor" "test" {
   }
 }
 `, endpoint, datasource)
-}
\ No newline at end of file
+}
+
+func testAccKafkaSupervisorConfig_suspended(endpoint, datasource string, suspended bool) string {
+	return fmt.Sprintf(`
+provider "druid" {
+  endpoint = "%s"
+}
+
+resource "druid_kafka_supervisor" "test" {
+  datasource = "%s"
+
+  timestamp_spec {
+    column = "__time"
+    format = "iso"
+  }
+
+  topic = "test-topic"
+
+  input_format {
+    type = "json"
+  }
+
+  consumer_properties = {
+    "bootstrap.servers" = "localhost:9092"
+  }
+
+  suspended = %t
+}
+`, endpoint, datasource, suspended)
+}
+
+func testAccKafkaSupervisorConfig_supervisorID(endpoint, datasource string) string {
+	return fmt.Sprintf(`
+provider "druid" {
+  endpoint = "%[1]s"
+}
+
+resource "druid_kafka_supervisor" "eu" {
+  datasource    = "%[2]s"
+  supervisor_id = "%[2]s-eu"
+
+  timestamp_spec {
+    column = "__time"
+    format = "iso"
+  }
+
+  topic = "events-eu"
+
+  input_format {
+    type = "json"
+  }
+
+  consumer_properties = {
+    "bootstrap.servers" = "kafka-eu:9092"
+  }
+}
+
+resource "druid_kafka_supervisor" "us" {
+  datasource    = "%[2]s"
+  supervisor_id = "%[2]s-us"
+
+  timestamp_spec {
+    column = "__time"
+    format = "iso"
+  }
+
+  topic = "events-us"
+
+  input_format {
+    type = "json"
+  }
+
+  consumer_properties = {
+    "bootstrap.servers" = "kafka-us:9092"
+  }
+}
+`, endpoint, datasource)
+}
+
+func testAccKafkaSupervisorConfig_transformSpec(endpoint, datasource, dimension string) string {
+	return fmt.Sprintf(`
+provider "druid" {
+  endpoint = "%s"
+}
+
+resource "druid_kafka_supervisor" "test" {
+  datasource = "%s"
+
+  timestamp_spec {
+    column = "__time"
+    format = "iso"
+  }
+
+  transform_spec {
+    transforms {
+      name       = "country_upper"
+      expression = "upper(country)"
+    }
+
+    filter {
+      type = "and"
+
+      fields {
+        type      = "selector"
+        dimension = "%s"
+        value     = "click"
+      }
+
+      fields {
+        type       = "expression"
+        expression = "price > 0"
+      }
+    }
+  }
+
+  topic = "test-topic"
+
+  input_format {
+    type = "json"
+  }
+
+  consumer_properties = {
+    "bootstrap.servers" = "localhost:9092"
+  }
+}
+`, endpoint, datasource, dimension)
+}
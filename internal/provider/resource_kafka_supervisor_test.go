@@ -1,9 +1,17 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -181,6 +189,55 @@ func TestBuildSupervisorSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "suspended spec",
+			input: map[string]interface{}{
+				"datasource": "test-datasource",
+				"timestamp_spec": []interface{}{
+					map[string]interface{}{
+						"column": "__time",
+						"format": "iso",
+					},
+				},
+				"topic": "test-topic",
+				"input_format": []interface{}{
+					map[string]interface{}{
+						"type": "json",
+					},
+				},
+				"consumer_properties": map[string]interface{}{
+					"bootstrap.servers": "localhost:9092",
+				},
+				"suspended": true,
+			},
+			expected: map[string]interface{}{
+				"type": "kafka",
+				"spec": map[string]interface{}{
+					"dataSchema": map[string]interface{}{
+						"dataSource": "test-datasource",
+						"timestampSpec": map[string]interface{}{
+							"column": "__time",
+							"format": "iso",
+						},
+					},
+					"ioConfig": map[string]interface{}{
+						"topic": "test-topic",
+						"inputFormat": map[string]interface{}{
+							"type": "json",
+						},
+						"consumerProperties": map[string]interface{}{
+							"bootstrap.servers": "localhost:9092",
+						},
+						"taskCount":         1,
+						"replicas":          1,
+						"taskDuration":      "PT1H",
+						"useEarliestOffset": false,
+						"completionTimeout": "PT30M",
+					},
+				},
+				"suspended": true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -195,6 +252,132 @@ func TestBuildSupervisorSpec(t *testing.T) {
 	}
 }
 
+func TestBuildSupervisorSpecWithSpecJSON(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{
+				"column": "__time",
+				"format": "iso",
+			},
+		},
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{
+				"type": "json",
+			},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+		"spec_json": `{
+			"spec": {
+				"ioConfig": {
+					"taskCount": 4,
+					"completionTimeout": null,
+					"consumerProperties": {"security.protocol": "SSL"}
+				},
+				"tuningConfig": {"type": "kafka", "maxRecordsPerPoll": 500}
+			}
+		}`,
+	})
+
+	result, err := buildSupervisorSpec(d)
+	require.NoError(t, err)
+
+	spec := result["spec"].(map[string]interface{})
+	ioConfig := spec["ioConfig"].(map[string]interface{})
+	assert.Equal(t, float64(4), ioConfig["taskCount"])
+	assert.NotContains(t, ioConfig, "completionTimeout")
+	assert.Equal(t, "PT1H", ioConfig["taskDuration"])
+	assert.Equal(t, map[string]interface{}{
+		"bootstrap.servers": "localhost:9092",
+		"security.protocol": "SSL",
+	}, ioConfig["consumerProperties"])
+	assert.Equal(t, map[string]interface{}{
+		"type":              "kafka",
+		"maxRecordsPerPoll": float64(500),
+	}, spec["tuningConfig"])
+	assert.Equal(t, "test-datasource", spec["dataSchema"].(map[string]interface{})["dataSource"])
+
+	// Merging leaves the typed attributes alone
+	assert.Equal(t, map[string]interface{}{"bootstrap.servers": "localhost:9092"}, d.Get("consumer_properties"))
+}
+
+func TestFlattenSupervisorSpecWithSpecJSON(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{"column": "__time"},
+		},
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{"type": "json"},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+		"spec_json": `{
+			"spec": {
+				"ioConfig": {
+					"taskCount": 4,
+					"completionTimeout": null,
+					"consumerProperties": {"security.protocol": "SSL"}
+				}
+			}
+		}`,
+	})
+
+	// The live spec is the merged spec Druid ran, with its defaults filled in
+	live, err := buildSupervisorSpec(d)
+	require.NoError(t, err)
+	var druidSpec map[string]interface{}
+	require.NoError(t, copyJSON(live, &druidSpec))
+	druidSpec["spec"].(map[string]interface{})["ioConfig"].(map[string]interface{})["replicas"] = float64(2)
+
+	require.NoError(t, flattenSupervisorSpec(d, druidSpec, false))
+
+	// Overridden keys keep their typed values, so the plan settles
+	assert.Equal(t, 1, d.Get("task_count"))
+	assert.Equal(t, "PT30M", d.Get("completion_timeout"))
+	assert.Equal(t, map[string]interface{}{"bootstrap.servers": "localhost:9092"}, d.Get("consumer_properties"))
+
+	// Other keys are still read back
+	assert.Equal(t, 2, d.Get("replicas"))
+
+	// The live spec is left untouched
+	ioConfig := druidSpec["spec"].(map[string]interface{})["ioConfig"].(map[string]interface{})
+	assert.Equal(t, float64(4), ioConfig["taskCount"])
+}
+
+func TestSuppressEquivalentJSON(t *testing.T) {
+	assert.True(t, suppressEquivalentJSON("spec_json", `{"a": 1, "b": {"c": [1, 2]}}`, `{"b":{"c":[1,2]},"a":1}`, nil))
+	assert.False(t, suppressEquivalentJSON("spec_json", `{"a": 1}`, `{"a": 2}`, nil))
+	assert.False(t, suppressEquivalentJSON("spec_json", `{"b": {"c": [1, 2]}}`, `{"b": {"c": [2, 1]}}`, nil))
+	assert.False(t, suppressEquivalentJSON("spec_json", `{"a": 1}`, `not json`, nil))
+}
+
+func TestBuildSupervisorSpecWithSupervisorID(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource":    "test-datasource",
+		"supervisor_id": "test-datasource-eu",
+	})
+
+	result, err := buildSupervisorSpec(d)
+	require.NoError(t, err)
+	assert.Equal(t, "test-datasource-eu", result["id"])
+	assert.Equal(t, "test-datasource-eu", expectedSupervisorID(d))
+
+	d = schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+	})
+
+	result, err = buildSupervisorSpec(d)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "id")
+	assert.Equal(t, "test-datasource", expectedSupervisorID(d))
+}
+
 func TestBuildDataSchema(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -264,6 +447,173 @@ func TestBuildDataSchema(t *testing.T) {
 	}
 }
 
+func TestBuildDataSchemaDimensions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+		"dimensions_spec": []interface{}{
+			map[string]interface{}{
+				"dimensions": []interface{}{
+					map[string]interface{}{
+						"name":                "session_id",
+						"create_bitmap_index": false,
+					},
+					map[string]interface{}{
+						"name": "attributes",
+						"type": "json",
+					},
+					map[string]interface{}{
+						"name": "tags",
+						"type": "auto",
+					},
+				},
+				"use_schema_discovery":   true,
+				"include_all_dimensions": true,
+			},
+		},
+	})
+
+	result := buildDataSchema(d)
+	assert.Equal(t, map[string]interface{}{
+		"dimensions": []interface{}{
+			map[string]interface{}{
+				"name":              "session_id",
+				"type":              "string",
+				"createBitmapIndex": false,
+			},
+			map[string]interface{}{
+				"name": "attributes",
+				"type": "json",
+			},
+			map[string]interface{}{
+				"name": "tags",
+				"type": "auto",
+			},
+		},
+		"useSchemaDiscovery":   true,
+		"includeAllDimensions": true,
+	}, result["dimensionsSpec"])
+}
+
+func TestValidateDimensionsSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		dimensions    []interface{}
+		expectedError string
+	}{
+		{
+			name: "valid dimensions",
+			dimensions: []interface{}{
+				map[string]interface{}{"name": "country", "multi_value_handling": "SORTED_SET", "create_bitmap_index": false},
+				map[string]interface{}{"name": "price", "type": "double"},
+			},
+		},
+		{
+			name: "duplicate dimension",
+			dimensions: []interface{}{
+				map[string]interface{}{"name": "country"},
+				map[string]interface{}{"name": "country", "type": "auto"},
+			},
+			expectedError: `dimensions_spec.0.dimensions.1: dimension "country" is listed more than once`,
+		},
+		{
+			name: "multi value handling on long dimension",
+			dimensions: []interface{}{
+				map[string]interface{}{"name": "count", "type": "long", "multi_value_handling": "ARRAY"},
+			},
+			expectedError: "dimensions_spec.0.dimensions.0: multi_value_handling is only supported by string dimensions",
+		},
+		{
+			name: "bitmap index on json dimension",
+			dimensions: []interface{}{
+				map[string]interface{}{"name": "attributes", "type": "json", "create_bitmap_index": false},
+			},
+			expectedError: "dimensions_spec.0.dimensions.0: create_bitmap_index is only supported by string dimensions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"dimensions_spec": []interface{}{
+					map[string]interface{}{"dimensions": tt.dimensions},
+				},
+			})
+			err := validateDimensionsSpec(d.Get("dimensions_spec.0").(map[string]interface{}), "dimensions_spec.0")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestFlattenDimensionsSpec(t *testing.T) {
+	// Druid reports createBitmapIndex for every dimension type
+	result := flattenDimensionsSpec(map[string]interface{}{
+		"dimensions": []interface{}{
+			map[string]interface{}{"name": "session_id", "type": "string", "createBitmapIndex": false},
+			map[string]interface{}{"name": "price", "type": "long", "createBitmapIndex": false},
+			map[string]interface{}{"name": "attributes", "type": "json", "createBitmapIndex": true},
+		},
+		"useSchemaDiscovery":   true,
+		"includeAllDimensions": false,
+	}, nil, map[string]bool{})
+
+	require.Len(t, result, 1)
+	ds := result[0].(map[string]interface{})
+	dims := ds["dimensions"].([]interface{})
+	assert.Equal(t, false, dims[0].(map[string]interface{})["create_bitmap_index"])
+	assert.Equal(t, true, dims[1].(map[string]interface{})["create_bitmap_index"])
+	assert.Equal(t, "json", dims[2].(map[string]interface{})["type"])
+	assert.Equal(t, true, ds["use_schema_discovery"])
+	assert.Equal(t, false, ds["include_all_dimensions"])
+
+	// Schema discovery alone keeps the block
+	result = flattenDimensionsSpec(map[string]interface{}{"useSchemaDiscovery": true}, nil, map[string]bool{})
+	assert.Len(t, result, 1)
+}
+
+func TestBuildDataSchemaTransformSpec(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+		"transform_spec": []interface{}{
+			map[string]interface{}{
+				"transforms": []interface{}{
+					map[string]interface{}{
+						"name":       "full_name",
+						"expression": "concat(first_name, ' ', last_name)",
+					},
+				},
+				"filter": []interface{}{
+					map[string]interface{}{
+						"type":      "selector",
+						"dimension": "event_type",
+						"value":     "click",
+					},
+				},
+			},
+		},
+	})
+
+	result := buildDataSchema(d)
+	assert.Equal(t, map[string]interface{}{
+		"transforms": []interface{}{
+			map[string]interface{}{
+				"type":       "expression",
+				"name":       "full_name",
+				"expression": "concat(first_name, ' ', last_name)",
+			},
+		},
+		"filter": map[string]interface{}{
+			"type":      "selector",
+			"dimension": "event_type",
+			"value":     "click",
+		},
+	}, result["transformSpec"])
+}
+
 func TestBuildIOConfig(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -346,6 +696,44 @@ func TestBuildIOConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "IO config with message rejection and timing",
+			input: map[string]interface{}{
+				"topic": "test-topic",
+				"input_format": []interface{}{
+					map[string]interface{}{
+						"type": "json",
+					},
+				},
+				"consumer_properties": map[string]interface{}{
+					"bootstrap.servers": "localhost:9092",
+				},
+				"late_message_rejection_period":  "PT1H",
+				"early_message_rejection_period": "PT2H",
+				"start_delay":                    "PT10S",
+				"period":                         "PT1M",
+				"poll_timeout":                   250,
+			},
+			expected: map[string]interface{}{
+				"topic": "test-topic",
+				"inputFormat": map[string]interface{}{
+					"type": "json",
+				},
+				"consumerProperties": map[string]interface{}{
+					"bootstrap.servers": "localhost:9092",
+				},
+				"taskCount":                   1,
+				"replicas":                    1,
+				"taskDuration":                "PT1H",
+				"useEarliestOffset":           false,
+				"completionTimeout":           "PT30M",
+				"lateMessageRejectionPeriod":  "PT1H",
+				"earlyMessageRejectionPeriod": "PT2H",
+				"startDelay":                  "PT10S",
+				"period":                      "PT1M",
+				"pollTimeout":                 250,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -357,6 +745,209 @@ func TestBuildIOConfig(t *testing.T) {
 	}
 }
 
+func TestBuildIOConfigFlattenSpec(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{
+				"type": "json",
+				"flatten_spec": []interface{}{
+					map[string]interface{}{
+						"fields": []interface{}{
+							map[string]interface{}{
+								"type": "root",
+								"name": "user_id",
+							},
+							map[string]interface{}{
+								"type": "path",
+								"name": "city",
+								"expr": "$.geo.city",
+							},
+							map[string]interface{}{
+								"type": "jq",
+								"name": "first_tag",
+								"expr": ".tags[0]",
+							},
+							map[string]interface{}{
+								"type":  "tree",
+								"name":  "country",
+								"nodes": []interface{}{"geo", "country"},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	result := buildIOConfig(d)
+	assert.Equal(t, map[string]interface{}{
+		"type": "json",
+		"flattenSpec": map[string]interface{}{
+			"useFieldDiscovery": true,
+			"fields": []interface{}{
+				map[string]interface{}{
+					"type": "root",
+					"name": "user_id",
+				},
+				map[string]interface{}{
+					"type": "path",
+					"name": "city",
+					"expr": "$.geo.city",
+				},
+				map[string]interface{}{
+					"type": "jq",
+					"name": "first_tag",
+					"expr": ".tags[0]",
+				},
+				map[string]interface{}{
+					"type":  "tree",
+					"name":  "country",
+					"nodes": []interface{}{"geo", "country"},
+				},
+			},
+		},
+	}, result["inputFormat"])
+}
+
+func TestFlattenIOConfigTiming(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"period": "PT30S",
+	})
+	require.NoError(t, flattenSupervisorSpec(d, map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"ioConfig": map[string]interface{}{
+				"topic":                             "test-topic",
+				"startDelay":                        "PT5S",
+				"period":                            "PT30S",
+				"pollTimeout":                       float64(500),
+				"lateMessageRejectionStartDateTime": "2024-01-01T00:00:00.000Z",
+				"earlyMessageRejectionPeriod":       "PT7200S",
+			},
+		},
+	}, false))
+
+	// Druid's defaults are only kept when configured
+	assert.Empty(t, d.Get("start_delay"))
+	assert.Equal(t, "PT30S", d.Get("period"))
+	assert.Equal(t, 500, d.Get("poll_timeout"))
+	assert.Equal(t, "2024-01-01T00:00:00.000Z", d.Get("late_message_rejection_start_date_time"))
+	assert.Equal(t, "PT7200S", d.Get("early_message_rejection_period"))
+}
+
+func TestMessageRejectionValidation(t *testing.T) {
+	config := func(extra map[string]interface{}) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"datasource": "test-datasource",
+			"timestamp_spec": []interface{}{
+				map[string]interface{}{"column": "__time"},
+			},
+			"topic":               "test-topic",
+			"input_format":        []interface{}{map[string]interface{}{"type": "json"}},
+			"consumer_properties": map[string]interface{}{"bootstrap.servers": "localhost:9092"},
+		}
+		for k, v := range extra {
+			raw[k] = v
+		}
+		return terraform.NewResourceConfigRaw(raw)
+	}
+
+	valid := []map[string]interface{}{
+		{"late_message_rejection_period": "PT1H", "early_message_rejection_period": "P1D"},
+		{"late_message_rejection_start_date_time": "2024-01-01T00:00:00Z"},
+		{"start_delay": "PT5S", "period": "PT30S", "poll_timeout": 100},
+	}
+	for _, extra := range valid {
+		assert.False(t, resourceKafkaSupervisor().Validate(config(extra)).HasError(), extra)
+	}
+
+	invalid := []map[string]interface{}{
+		{"late_message_rejection_period": "1h"},
+		{"late_message_rejection_period": "P1M"},
+		{"early_message_rejection_period": "PT"},
+		{"late_message_rejection_start_date_time": "last week"},
+		{"late_message_rejection_period": "PT1H", "late_message_rejection_start_date_time": "2024-01-01"},
+		{"start_delay": "5s"},
+		{"period": "30"},
+		{"poll_timeout": 0},
+	}
+	for _, extra := range invalid {
+		assert.True(t, resourceKafkaSupervisor().Validate(config(extra)).HasError(), extra)
+	}
+}
+
+func TestSuppressEquivalentDateTimes(t *testing.T) {
+	assert.True(t, suppressEquivalentDateTimes("late_message_rejection_start_date_time", "2024-01-01T00:00:00.000Z", "2024-01-01", nil))
+	assert.True(t, suppressEquivalentDateTimes("late_message_rejection_start_date_time", "2024-01-01T08:00:00.000Z", "2024-01-01T00:00:00-08:00", nil))
+	assert.False(t, suppressEquivalentDateTimes("late_message_rejection_start_date_time", "2024-01-01T00:00:00.000Z", "2024-01-02", nil))
+
+	// Druid normalizes the timestamp spec's missingValue the same way
+	missingValue := resourceKafkaSupervisor().Schema["timestamp_spec"].Elem.(*schema.Resource).Schema["missing_value"]
+	assert.True(t, missingValue.DiffSuppressFunc("timestamp_spec.0.missing_value", "2010-01-01T00:00:00.000Z", "2010-01-01T00:00:00Z", nil))
+	_, errs := missingValue.ValidateFunc("2010-01-01T00:00:00Z", "timestamp_spec.0.missing_value")
+	assert.Empty(t, errs)
+	_, errs = missingValue.ValidateFunc("yesterday", "timestamp_spec.0.missing_value")
+	assert.NotEmpty(t, errs)
+}
+
+func TestValidateFlattenSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		field         map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:  "root field",
+			field: map[string]interface{}{"type": "root", "name": "user_id"},
+		},
+		{
+			name:          "root field with expr",
+			field:         map[string]interface{}{"type": "root", "name": "user_id", "expr": "$.user_id"},
+			expectedError: "flatten_spec.fields.0: root field does not take expr or nodes",
+		},
+		{
+			name:          "path field without expr",
+			field:         map[string]interface{}{"type": "path", "name": "city"},
+			expectedError: "flatten_spec.fields.0: path field requires expr",
+		},
+		{
+			name:          "jq field without expr",
+			field:         map[string]interface{}{"type": "jq", "name": "city"},
+			expectedError: "flatten_spec.fields.0: jq field requires expr",
+		},
+		{
+			name:          "tree field without nodes",
+			field:         map[string]interface{}{"type": "tree", "name": "country"},
+			expectedError: "flatten_spec.fields.0: tree field requires nodes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"input_format": []interface{}{
+					map[string]interface{}{
+						"type": "json",
+						"flatten_spec": []interface{}{
+							map[string]interface{}{
+								"fields": []interface{}{tt.field},
+							},
+						},
+					},
+				},
+			})
+			err := validateFlattenSpec(d.Get("input_format.0.flatten_spec.0").(map[string]interface{}), "flatten_spec")
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
 func TestBuildTuningConfig(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -401,8 +992,8 @@ func TestBuildTuningConfig(t *testing.T) {
 						"chat_retries":                5,
 						"index_spec": []interface{}{
 							map[string]interface{}{
-								"bitmap": map[string]interface{}{
-									"type": "roaring",
+								"bitmap": []interface{}{
+									map[string]interface{}{"type": "roaring"},
 								},
 								"dimension_compression": "lz4",
 								"metric_compression":    "lz4",
@@ -441,18 +1032,1329 @@ func TestBuildTuningConfig(t *testing.T) {
 	}
 }
 
-func TestResourceKafkaSupervisorSchema(t *testing.T) {
-	resource := resourceKafkaSupervisor()
-	
-	// Test required fields
-	assert.True(t, resource.Schema["datasource"].Required)
-	assert.True(t, resource.Schema["timestamp_spec"].Required)
-	assert.True(t, resource.Schema["input_format"].Required)
-	assert.True(t, resource.Schema["consumer_properties"].Required)
+func TestBuildTuningConfigOnlyConfigured(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"tuning_config": []interface{}{
+			map[string]interface{}{
+				"max_parse_exceptions":       0,
+				"log_parse_exceptions":       false,
+				"max_saved_parse_exceptions": 1,
+				"index_spec": []interface{}{
+					map[string]interface{}{
+						"metric_compression": "zstd",
+					},
+				},
+			},
+		},
+	})
+	tuningConfig := firstBlock(d.Get("tuning_config"))
+
+	// Without the raw configuration, zero and false values are indistinguishable
+	// from unset options and are left to Druid.
+	unset := func(string) bool { return false }
+	assert.Equal(t, map[string]interface{}{
+		"type":                    "kafka",
+		"maxSavedParseExceptions": 1,
+		"indexSpec": map[string]interface{}{
+			"metricCompression": "zstd",
+		},
+	}, buildTuningConfigBlock(tuningConfig, unset))
+
+	// Explicitly configured zero and false values are sent.
+	configured := func(key string) bool {
+		return key == "max_parse_exceptions" || key == "log_parse_exceptions"
+	}
+	tc := buildTuningConfigBlock(tuningConfig, configured)
+	assert.Equal(t, 0, tc["maxParseExceptions"])
+	assert.Equal(t, false, tc["logParseExceptions"])
+	assert.NotContains(t, tc, "maxRowsInMemory")
+	assert.NotContains(t, tc, "chatRetries")
+
+	assert.Nil(t, buildTuningConfigBlock(nil, unset))
+}
+
+func TestBuildTuningConfigCoverage(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"tuning_config": []interface{}{
+			map[string]interface{}{
+				"partitions_spec": []interface{}{
+					map[string]interface{}{
+						"max_rows_per_segment": 3000000,
+						"max_total_rows":       20000000,
+					},
+				},
+				"handoff_condition_timeout": 600000,
+				"offset_fetch_period":       "PT1M",
+				"max_records_per_poll":      1000,
+				"max_columns_to_merge":      -1,
+				"report_parse_exceptions":   true,
+				"index_spec_for_intermediate_persists": []interface{}{
+					map[string]interface{}{
+						"dimension_compression": "uncompressed",
+						"metric_compression":    "none",
+					},
+				},
+				"appendable_index_spec": []interface{}{
+					map[string]interface{}{
+						"preserve_existing_metrics": true,
+					},
+				},
+			},
+		},
+	})
+	tc := buildTuningConfig(d)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":              "dynamic",
+		"maxRowsPerSegment": 3000000,
+		"maxTotalRows":      20000000,
+	}, tc["partitionsSpec"])
+	assert.Equal(t, 3000000, tc["maxRowsPerSegment"])
+	assert.Equal(t, 20000000, tc["maxTotalRows"])
+	assert.Equal(t, 600000, tc["handoffConditionTimeout"])
+	assert.Equal(t, "PT1M", tc["offsetFetchPeriod"])
+	assert.Equal(t, 1000, tc["maxRecordsPerPoll"])
+	assert.Equal(t, -1, tc["maxColumnsToMerge"])
+	assert.Equal(t, true, tc["reportParseExceptions"])
+	assert.Equal(t, "uncompressed", tc["indexSpecForIntermediatePersists"].(map[string]interface{})["dimensionCompression"])
+	assert.Equal(t, map[string]interface{}{
+		"type":                    "onheap",
+		"preserveExistingMetrics": true,
+	}, tc["appendableIndexSpec"])
+	assert.NotContains(t, tc, "indexSpec")
+}
+
+func TestPartitionsSpecConflicts(t *testing.T) {
+	config := func(tuningConfig map[string]interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"datasource": "test-datasource",
+			"timestamp_spec": []interface{}{
+				map[string]interface{}{"column": "__time"},
+			},
+			"topic":               "test-topic",
+			"input_format":        []interface{}{map[string]interface{}{"type": "json"}},
+			"consumer_properties": map[string]interface{}{"bootstrap.servers": "localhost:9092"},
+			"tuning_config":       []interface{}{tuningConfig},
+		})
+	}
+	partitionsSpec := []interface{}{
+		map[string]interface{}{"max_rows_per_segment": 1000000},
+	}
+
+	diags := resourceKafkaSupervisor().Validate(config(map[string]interface{}{
+		"partitions_spec": partitionsSpec,
+	}))
+	assert.False(t, diags.HasError())
+
+	diags = resourceKafkaSupervisor().Validate(config(map[string]interface{}{
+		"partitions_spec":      partitionsSpec,
+		"max_rows_per_segment": 1000000,
+	}))
+	assert.True(t, diags.HasError())
+
+	diags = resourceKafkaSupervisor().Validate(config(map[string]interface{}{
+		"partitions_spec": []interface{}{
+			map[string]interface{}{"type": "hashed"},
+		},
+	}))
+	assert.True(t, diags.HasError())
+}
+
+func TestValidateTuningConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      map[string]interface{}
+		configured []string
+		wantErr    string
+	}{
+		{
+			name:  "defaults",
+			input: map[string]interface{}{"max_parse_exceptions": 2147483647},
+		},
+		{
+			name:  "report parse exceptions with default max",
+			input: map[string]interface{}{"report_parse_exceptions": true, "max_parse_exceptions": 2147483647},
+		},
+		{
+			name:       "report parse exceptions with explicit zero",
+			input:      map[string]interface{}{"report_parse_exceptions": true, "max_parse_exceptions": 0, "max_saved_parse_exceptions": 1},
+			configured: []string{"max_parse_exceptions"},
+		},
+		{
+			name:       "report parse exceptions with explicit max",
+			input:      map[string]interface{}{"report_parse_exceptions": true, "max_parse_exceptions": 100},
+			configured: []string{"max_parse_exceptions"},
+			wantErr:    "tuning_config.0: report_parse_exceptions sets max_parse_exceptions to 0",
+		},
+		{
+			name:    "report parse exceptions with saved exceptions",
+			input:   map[string]interface{}{"report_parse_exceptions": true, "max_saved_parse_exceptions": 5},
+			wantErr: "limits max_saved_parse_exceptions to 1",
+		},
+		{
+			name:       "saved exceptions within max",
+			input:      map[string]interface{}{"max_parse_exceptions": 10, "max_saved_parse_exceptions": 11},
+			configured: []string{"max_parse_exceptions"},
+		},
+		{
+			name:       "saved exceptions beyond max",
+			input:      map[string]interface{}{"max_parse_exceptions": 10, "max_saved_parse_exceptions": 50},
+			configured: []string{"max_parse_exceptions"},
+			wantErr:    "max_saved_parse_exceptions (50) can never be reached",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configured := func(key string) bool {
+				for _, k := range tt.configured {
+					if k == key {
+						return true
+					}
+				}
+				return false
+			}
+			err := validateTuningConfig(tt.input, configured, "tuning_config.0")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlockAttrConfigured(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"tuning_config": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"max_parse_exceptions": cty.NumberIntVal(0),
+				"max_rows_in_memory":   cty.NullVal(cty.Number),
+			}),
+		}),
+		"idle_config": cty.ListValEmpty(cty.EmptyObject),
+	})
+
+	assert.True(t, blockAttrConfigured(config, "tuning_config", "max_parse_exceptions"))
+	assert.False(t, blockAttrConfigured(config, "tuning_config", "max_rows_in_memory"))
+	assert.False(t, blockAttrConfigured(config, "idle_config", "enabled"))
+	assert.False(t, blockAttrConfigured(cty.NullVal(config.Type()), "tuning_config", "max_parse_exceptions"))
+}
+
+func TestFlattenTuningConfig(t *testing.T) {
+	// A tuningConfig as returned by Druid, with defaults filled in
+	druidTuningConfig := map[string]interface{}{
+		"type":                    "kafka",
+		"maxRowsPerSegment":       float64(3000000),
+		"maxTotalRows":            float64(20000000),
+		"maxColumnsToMerge":       float64(-1),
+		"handoffConditionTimeout": float64(900000),
+		"offsetFetchPeriod":       "PT30S",
+		"reportParseExceptions":   false,
+		"partitionsSpec": map[string]interface{}{
+			"type":              "dynamic",
+			"maxRowsPerSegment": float64(3000000),
+			"maxTotalRows":      float64(20000000),
+		},
+		"indexSpec": map[string]interface{}{
+			"dimensionCompression": "lz4",
+		},
+		"indexSpecForIntermediatePersists": map[string]interface{}{
+			"dimensionCompression": "lz4",
+		},
+		"appendableIndexSpec": map[string]interface{}{
+			"type":                    "onheap",
+			"preserveExistingMetrics": false,
+		},
+	}
+
+	t.Run("defaults are dropped", func(t *testing.T) {
+		result := flattenTuningConfig(druidTuningConfig, map[string]interface{}{}, false)
+		tc := result[0].(map[string]interface{})
+		
+		defaulted := flattenTuningConfig(map[string]interface{}{
+			"maxRowsInMemory":    float64(150000),
+			"maxParseExceptions": float64(2147483647),
+			"chatRetries":        float64(8),
+			"httpTimeout":        "PT10S",
+		}, map[string]interface{}{"chat_retries": 8}, false)[0].(map[string]interface{})
+		assert.Equal(t, 0, defaulted["max_rows_in_memory"])
+		assert.Equal(t, 0, defaulted["max_parse_exceptions"])
+		assert.Equal(t, 8, defaulted["chat_retries"])
+		assert.Equal(t, "", defaulted["http_timeout"])
+
+		assert.Equal(t, 3000000, tc["max_rows_per_segment"])
+		assert.Equal(t, 20000000, tc["max_total_rows"])
+		assert.Equal(t, 0, tc["max_columns_to_merge"])
+		assert.Equal(t, 0, tc["handoff_condition_timeout"])
+		assert.Equal(t, "", tc["offset_fetch_period"])
+		assert.Empty(t, tc["partitions_spec"])
+		assert.NotContains(t, tc, "index_spec")
+		assert.NotContains(t, tc, "index_spec_for_intermediate_persists")
+		assert.NotContains(t, tc, "appendable_index_spec")
+	})
+
+	t.Run("managed blocks are read back", func(t *testing.T) {
+		prior := map[string]interface{}{
+			"max_rows_per_segment":      5000000,
+			"max_total_rows":            0,
+			"handoff_condition_timeout": 900000,
+			"partitions_spec": []interface{}{
+				map[string]interface{}{"type": "dynamic", "max_rows_per_segment": 3000000},
+			},
+			"index_spec_for_intermediate_persists": []interface{}{
+				map[string]interface{}{"dimension_compression": "lz4"},
+			},
+			"appendable_index_spec": []interface{}{
+				map[string]interface{}{"type": "onheap"},
+			},
+		}
+		result := flattenTuningConfig(druidTuningConfig, prior, false)
+		tc := result[0].(map[string]interface{})
+
+		assert.Equal(t, 900000, tc["handoff_condition_timeout"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "dynamic", "max_rows_per_segment": 3000000, "max_total_rows": 20000000},
+		}, tc["partitions_spec"])
+		assert.Equal(t, 5000000, tc["max_rows_per_segment"])
+		assert.Equal(t, 0, tc["max_total_rows"])
+		assert.NotContains(t, tc, "index_spec")
+		assert.Equal(t, "lz4", tc["index_spec_for_intermediate_persists"].([]interface{})[0].(map[string]interface{})["dimension_compression"])
+		assert.Equal(t, "onheap", tc["appendable_index_spec"].([]interface{})[0].(map[string]interface{})["type"])
+	})
+}
+
+func TestFlattenSupervisorSpec(t *testing.T) {
+	// A spec as returned by Druid, with server-side defaults filled in
+	druidSpec := map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": map[string]interface{}{
+				"dataSource": "test-datasource",
+				"timestampSpec": map[string]interface{}{
+					"column":       "timestamp",
+					"format":       "iso",
+					"missingValue": nil,
+				},
+				"dimensionsSpec": map[string]interface{}{
+					"dimensions": []interface{}{
+						map[string]interface{}{
+							"name":               "user_id",
+							"type":               "string",
+							"multiValueHandling": "SORTED_ARRAY",
+							"createBitmapIndex":  true,
+						},
+						"country",
+					},
+					"dimensionExclusions": []interface{}{"timestamp", "count", "kafka.timestamp"},
+				},
+				"metricsSpec": []interface{}{
+					map[string]interface{}{
+						"name": "count",
+						"type": "count",
+					},
+				},
+				"granularitySpec": map[string]interface{}{
+					"type":               "uniform",
+					"segmentGranularity": "DAY",
+					"queryGranularity":   map[string]interface{}{"type": "none"},
+					"rollup":             true,
+				},
+				"transformSpec": map[string]interface{}{
+					"filter":     nil,
+					"transforms": []interface{}{},
+				},
+			},
+			"ioConfig": map[string]interface{}{
+				"topic": "test-topic",
+				"inputFormat": map[string]interface{}{
+					"type":            "json",
+					"keepNullColumns": false,
+					"flattenSpec": map[string]interface{}{
+						"useFieldDiscovery": true,
+						"fields":            []interface{}{},
+					},
+				},
+				"consumerProperties": map[string]interface{}{
+					"bootstrap.servers": "localhost:9092",
+				},
+				"taskCount":                         float64(3),
+				"replicas":                          float64(2),
+				"taskDuration":                      "PT3600S",
+				"useEarliestOffset":                 true,
+				"completionTimeout":                 "PT1800S",
+				"idleConfig":                        nil,
+				"startDelay":                        "PT5S",
+				"period":                            "PT30S",
+				"pollTimeout":                       float64(100),
+				"lateMessageRejectionPeriod":        nil,
+				"earlyMessageRejectionPeriod":       nil,
+				"lateMessageRejectionStartDateTime": nil,
+			},
+			"tuningConfig": map[string]interface{}{
+				"type":              "kafka",
+				"maxRowsPerSegment": float64(5000000),
+				"maxRowsInMemory":   float64(150000),
+				"workerThreads":     nil,
+				"indexSpec": map[string]interface{}{
+					"bitmap":               map[string]interface{}{"type": "roaring"},
+					"dimensionCompression": "lz4",
+					"metricCompression":    "lz4",
+				},
+			},
+		},
+		"context":   map[string]interface{}{"priority": float64(75)},
+		"suspended": false,
+	}
+
+	t.Run("unmanaged blocks are left out", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+		require.NoError(t, flattenSupervisorSpec(d, druidSpec, false))
+
+		assert.Equal(t, "test-datasource", d.Get("datasource"))
+		assert.Equal(t, "timestamp", d.Get("timestamp_spec.0.column"))
+		assert.Equal(t, "iso", d.Get("timestamp_spec.0.format"))
+		assert.Equal(t, "user_id", d.Get("dimensions_spec.0.dimensions.0.name"))
+		assert.Equal(t, "", d.Get("dimensions_spec.0.dimensions.0.multi_value_handling"))
+		assert.Equal(t, "country", d.Get("dimensions_spec.0.dimensions.1.name"))
+		assert.Equal(t, []interface{}{"kafka.timestamp"}, d.Get("dimensions_spec.0.dimension_exclusions"))
+		assert.Equal(t, "count", d.Get("metrics_spec.0.name"))
+		assert.Equal(t, "test-topic", d.Get("topic"))
+		assert.Equal(t, "json", d.Get("input_format.0.type"))
+		assert.Empty(t, d.Get("input_format.0.flatten_spec"))
+		assert.Equal(t, map[string]interface{}{"bootstrap.servers": "localhost:9092"}, d.Get("consumer_properties"))
+		assert.Equal(t, 3, d.Get("task_count"))
+		assert.Equal(t, 2, d.Get("replicas"))
+		assert.Equal(t, "PT3600S", d.Get("task_duration"))
+		assert.Equal(t, true, d.Get("use_earliest_offset"))
+		assert.Equal(t, map[string]interface{}{"priority": "75"}, d.Get("context"))
+		assert.Empty(t, d.Get("granularity_spec"))
+		assert.Empty(t, d.Get("transform_spec"))
+		assert.Empty(t, d.Get("tuning_config"))
+		assert.Empty(t, d.Get("idle_config"))
+		assert.Empty(t, d.Get("start_delay"))
+		assert.Empty(t, d.Get("period"))
+		assert.Equal(t, 0, d.Get("poll_timeout"))
+		assert.Empty(t, d.Get("late_message_rejection_period"))
+		assert.Empty(t, d.Get("autoscaler_config"))
+	})
+
+	t.Run("managed blocks are read back", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+			"dimensions_spec": []interface{}{
+				map[string]interface{}{
+					"dimensions": []interface{}{
+						map[string]interface{}{
+							"name":                 "user_id",
+							"multi_value_handling": "sorted_array",
+						},
+					},
+					"dimension_exclusions": []interface{}{"count"},
+				},
+			},
+			"granularity_spec": []interface{}{
+				map[string]interface{}{
+					"segment_granularity": "HOUR",
+				},
+			},
+			"tuning_config": []interface{}{
+				map[string]interface{}{
+					"max_rows_per_segment": 1000000,
+				},
+			},
+		})
+		require.NoError(t, flattenSupervisorSpec(d, druidSpec, false))
+
+		assert.Equal(t, "SORTED_ARRAY", d.Get("dimensions_spec.0.dimensions.0.multi_value_handling"))
+		assert.Equal(t, []interface{}{"count", "kafka.timestamp"}, d.Get("dimensions_spec.0.dimension_exclusions"))
+		assert.Equal(t, "DAY", d.Get("granularity_spec.0.segment_granularity"))
+		assert.Equal(t, "NONE", d.Get("granularity_spec.0.query_granularity"))
+		assert.Equal(t, 5000000, d.Get("tuning_config.0.max_rows_per_segment"))
+		assert.Equal(t, 0, d.Get("tuning_config.0.worker_threads"))
+		assert.Empty(t, d.Get("tuning_config.0.index_spec"))
+	})
+
+	t.Run("import reads back every block", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+		require.NoError(t, flattenSupervisorSpec(d, druidSpec, true))
+
+		assert.Equal(t, "DAY", d.Get("granularity_spec.0.segment_granularity"))
+		assert.Len(t, d.Get("tuning_config"), 1)
+		
+		// Druid's defaults are left to Druid
+		assert.Equal(t, 0, d.Get("tuning_config.0.max_rows_per_segment"))
+		assert.Equal(t, 0, d.Get("tuning_config.0.max_rows_in_memory"))
+		assert.Empty(t, d.Get("tuning_config.0.index_spec"))
+		assert.Empty(t, d.Get("tuning_config.0.index_spec_for_intermediate_persists"))
+	})
+
+	t.Run("import reads back non-default index specs", func(t *testing.T) {
+		spec := map[string]interface{}{}
+		require.NoError(t, copyJSON(druidSpec, &spec))
+		tuningConfig := spec["spec"].(map[string]interface{})["tuningConfig"].(map[string]interface{})
+		tuningConfig["indexSpec"].(map[string]interface{})["dimensionCompression"] = "zstd"
+		
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+		require.NoError(t, flattenSupervisorSpec(d, spec, true))
+
+		assert.Len(t, d.Get("tuning_config.0.index_spec"), 1)
+		assert.Equal(t, "zstd", d.Get("tuning_config.0.index_spec.0.dimension_compression"))
+		assert.Empty(t, d.Get("tuning_config.0.index_spec.0.bitmap"))
+		assert.Equal(t, "", d.Get("tuning_config.0.index_spec.0.metric_compression"))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		input := map[string]interface{}{
+			"datasource": "test-datasource",
+			"timestamp_spec": []interface{}{
+				map[string]interface{}{
+					"column": "__time",
+					"format": "iso",
+				},
+			},
+			"metrics_spec": []interface{}{
+				map[string]interface{}{
+					"name":       "revenue",
+					"type":       "doubleSum",
+					"field_name": "price",
+				},
+			},
+			"transform_spec": []interface{}{
+				map[string]interface{}{
+					"transforms": []interface{}{
+						map[string]interface{}{
+							"name":       "country_upper",
+							"expression": "upper(country)",
+						},
+					},
+					"filter": []interface{}{
+						map[string]interface{}{
+							"type": "and",
+							"fields": []interface{}{
+								map[string]interface{}{
+									"type":      "in",
+									"dimension": "country",
+									"values":    []interface{}{"US", "CA"},
+								},
+								map[string]interface{}{
+									"type": "not",
+									"field": []interface{}{
+										map[string]interface{}{
+											"type":         "bound",
+											"dimension":    "price",
+											"lower":        "0",
+											"lower_strict": true,
+											"ordering":     "numeric",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"topic_pattern": "events-.*",
+			"input_format": []interface{}{
+				map[string]interface{}{
+					"type": "json",
+					"flatten_spec": []interface{}{
+						map[string]interface{}{
+							"use_field_discovery": false,
+							"fields": []interface{}{
+								map[string]interface{}{
+									"type": "root",
+									"name": "user_id",
+								},
+								map[string]interface{}{
+									"type": "path",
+									"name": "city",
+									"expr": "$.geo.city",
+								},
+							},
+						},
+					},
+				},
+			},
+			"consumer_properties": map[string]interface{}{
+				"bootstrap.servers": "localhost:9092",
+			},
+			"task_count": 2,
+			"idle_config": []interface{}{
+				map[string]interface{}{
+					"enabled":               true,
+					"inactive_after_millis": 600000,
+				},
+			},
+			"context": map[string]interface{}{"priority": "75"},
+		}
+		spec, err := buildSupervisorSpec(schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, input))
+		require.NoError(t, err)
+
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+		require.NoError(t, flattenSupervisorSpec(d, spec, false))
+
+		result, err := buildSupervisorSpec(d)
+		require.NoError(t, err)
+		assert.Equal(t, spec, result)
+	})
+}
+
+func TestFlattenSupervisorStatus(t *testing.T) {
+	d := resourceKafkaSupervisor().TestResourceData()
+	err := flattenSupervisorStatus(d, &SupervisorStatus{
+		ID:            "test-supervisor",
+		State:         "UNHEALTHY_SUPERVISOR",
+		DetailedState: "UNABLE_TO_CONNECT_TO_STREAM",
+		Healthy:       false,
+		Partitions:    3,
+		ActiveTasks: []SupervisorTask{
+			{ID: "index_kafka_test_1", StartTime: "2024-01-01T00:00:00.000Z", RemainingSeconds: 600},
+		},
+		AggregateLag:       42,
+		MinimumLag:         map[string]int64{"0": 40, "1": 2},
+		OffsetsLastUpdated: "2024-01-01T00:30:00.000Z",
+		RecentErrors: []SupervisorError{
+			{Timestamp: "2024-01-01T00:29:00.000Z", ExceptionClass: "org.apache.kafka.common.errors.TimeoutException", Message: "Timeout expired", StreamException: true},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "UNABLE_TO_CONNECT_TO_STREAM", d.Get("detailed_state"))
+	assert.Equal(t, false, d.Get("healthy"))
+	assert.Equal(t, 3, d.Get("partitions"))
+	assert.Equal(t, "index_kafka_test_1", d.Get("active_tasks.0.id"))
+	assert.Equal(t, 600, d.Get("active_tasks.0.remaining_seconds"))
+	assert.Empty(t, d.Get("publishing_tasks"))
+	assert.Equal(t, 42, d.Get("aggregate_lag"))
+	assert.Equal(t, map[string]interface{}{"0": 40, "1": 2}, d.Get("minimum_lag"))
+	assert.Equal(t, "2024-01-01T00:30:00.000Z", d.Get("offsets_last_updated"))
+	assert.Equal(t, "Timeout expired", d.Get("recent_errors.0.message"))
+	assert.Equal(t, true, d.Get("recent_errors.0.stream_exception"))
+}
+
+func TestResourceKafkaSupervisorImport(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.AddSupervisor("imported-supervisor", "RUNNING")
+	mockServer.SetSupervisorSpec("imported-supervisor", map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": map[string]interface{}{
+				"dataSource": "imported",
+				"timestampSpec": map[string]interface{}{
+					"column": "__time",
+					"format": "millis",
+				},
+			},
+			"ioConfig": map[string]interface{}{
+				"topic": "imported-topic",
+				"inputFormat": map[string]interface{}{
+					"type": "json",
+				},
+				"consumerProperties": map[string]interface{}{
+					"bootstrap.servers": "localhost:9092",
+				},
+				"taskCount": float64(2),
+			},
+			"tuningConfig": map[string]interface{}{
+				"type":              "kafka",
+				"maxRowsPerSegment": float64(1000000),
+			},
+		},
+	})
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := resourceKafkaSupervisor().TestResourceData()
+	d.SetId("imported-supervisor")
+
+	result, err := resourceKafkaSupervisorImport(context.Background(), d, client)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	imported := result[0]
+	assert.Equal(t, "imported-supervisor", imported.Id())
+	assert.Equal(t, "imported-supervisor", imported.Get("supervisor_id"))
+	assert.Equal(t, "imported", imported.Get("datasource"))
+	assert.Equal(t, "millis", imported.Get("timestamp_spec.0.format"))
+	assert.Equal(t, "imported-topic", imported.Get("topic"))
+	assert.Equal(t, 2, imported.Get("task_count"))
+	assert.Equal(t, 1000000, imported.Get("tuning_config.0.max_rows_per_segment"))
+	assert.Equal(t, true, imported.Get("wait_for_healthy"))
+	assert.Equal(t, "terminate", imported.Get("deletion_policy"))
+
+	// Importing a supervisor that does not exist fails
+	d = resourceKafkaSupervisor().TestResourceData()
+	d.SetId("missing-supervisor")
+
+	_, err = resourceKafkaSupervisorImport(context.Background(), d, client)
+	assert.Error(t, err)
+}
+
+func TestWaitForSupervisorHealthy(t *testing.T) {
+	tests := []struct {
+		name          string
+		states        []string
+		topics        []string
+		previous      *supervisorSnapshot
+		minPolls      int
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:   "becomes healthy",
+			states: []string{"PENDING", "RUNNING"},
+		},
+		{
+			name:   "waits while stopping",
+			states: []string{"STOPPING", "PENDING", "RUNNING"},
+		},
+		{
+			name:     "ignores the replaced supervisor's state",
+			states:   []string{"RUNNING", "PENDING", "RUNNING"},
+			topics:   []string{"old-topic"},
+			previous: &supervisorSnapshot{State: "RUNNING", Spec: map[string]interface{}{"topic": "old-topic"}},
+			minPolls: 3,
+		},
+		{
+			name:     "accepts the updated spec",
+			states:   []string{"RUNNING"},
+			topics:   []string{"old-topic", "new-topic"},
+			previous: &supervisorSnapshot{State: "RUNNING", Spec: map[string]interface{}{"topic": "old-topic"}},
+			minPolls: 2,
+		},
+		{
+			name:   "becomes idle",
+			states: []string{"PENDING", "IDLE"},
+		},
+		{
+			name:          "becomes unhealthy",
+			states:        []string{"PENDING", "UNHEALTHY_SUPERVISOR"},
+			expectError:   true,
+			errorContains: "Failed to construct kafka consumer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls, specPolls := 0, 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/status") {
+					topic := tt.topics[len(tt.topics)-1]
+					if specPolls < len(tt.topics) {
+						topic = tt.topics[specPolls]
+					}
+					specPolls++
+					json.NewEncoder(w).Encode(map[string]interface{}{"topic": topic})
+					return
+				}
+				
+				state := tt.states[len(tt.states)-1]
+				if polls < len(tt.states) {
+					state = tt.states[polls]
+				}
+				polls++
+				
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id": "test-supervisor",
+					"payload": map[string]interface{}{
+						"state": state,
+						"recentErrors": []interface{}{
+							map[string]interface{}{
+								"exceptionClass": "org.apache.kafka.common.KafkaException",
+								"message":        "Failed to construct kafka consumer",
+							},
+						},
+					},
+				})
+			}))
+			defer server.Close()
+
+			client := &Client{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+			}
+
+			err := waitForSupervisorHealthy(context.Background(), client, "test-supervisor", time.Minute, tt.previous)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, polls, tt.minPolls)
+			}
+		})
+	}
+}
+
+func TestSupervisorSpecForApply(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	pinnedSpec := map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": map[string]interface{}{
+				"dataSource": "test-datasource",
+			},
+		},
+	}
+	mockServer.AddSupervisorHistory("test-datasource", SupervisorHistoryEntry{
+		Spec:    pinnedSpec,
+		Version: "2024-01-01T00:00:00.000Z",
+	})
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	input := map[string]interface{}{
+		"datasource": "test-datasource",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{
+				"column": "__time",
+			},
+		},
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{
+				"type": "json",
+			},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+	}
+
+	// Without a pinned version the spec is built from the configuration
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, input)
+	spec, err := supervisorSpecForApply(context.Background(), client, d)
+	require.NoError(t, err)
+	expected, err := buildSupervisorSpec(d)
+	require.NoError(t, err)
+	assert.Equal(t, expected, spec)
+
+	// A pinned version submits the historical spec
+	input["spec_version"] = "2024-01-01T00:00:00.000Z"
+	input["suspended"] = true
+	d = schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, input)
+	spec, err = supervisorSpecForApply(context.Background(), client, d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": map[string]interface{}{
+				"dataSource": "test-datasource",
+			},
+		},
+		"suspended": true,
+	}, spec)
+
+	// An unknown version is an error
+	input["spec_version"] = "2023-01-01T00:00:00.000Z"
+	d = schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, input)
+	_, err = supervisorSpecForApply(context.Background(), client, d)
+	assert.Error(t, err)
+}
+
+func TestResourceKafkaSupervisorReadPinned(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	pinnedSpec := map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": map[string]interface{}{"dataSource": "test-datasource"},
+			"ioConfig":   map[string]interface{}{"topic": "test-topic", "taskCount": float64(2)},
+		},
+	}
+	mockServer.AddSupervisor("test-datasource", "RUNNING")
+	mockServer.AddSupervisorHistory("test-datasource", SupervisorHistoryEntry{
+		Spec:    pinnedSpec,
+		Version: "2024-01-01T00:00:00.000Z",
+	})
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	read := func(live map[string]interface{}, version string) *schema.ResourceData {
+		mockServer.SetSupervisorSpec("test-datasource", live)
+		d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+			"datasource":   "test-datasource",
+			"task_count":   1,
+			"spec_version": version,
+		})
+		d.SetId("test-datasource")
+		require.False(t, resourceKafkaSupervisorRead(context.Background(), d, client).HasError())
+		return d
+	}
+
+	// The pinned spec is running, so the pin and the typed attributes are kept
+	d := read(map[string]interface{}{"type": "kafka", "spec": pinnedSpec["spec"], "suspended": true}, "2024-01-01T00:00:00.000Z")
+	assert.Equal(t, "2024-01-01T00:00:00.000Z", d.Get("spec_version"))
+	assert.Equal(t, 1, d.Get("task_count"))
+
+	// A spec changed outside Terraform clears the pin, so the next plan resubmits it
+	d = read(map[string]interface{}{
+		"type": "kafka",
+		"spec": map[string]interface{}{
+			"dataSchema": map[string]interface{}{"dataSource": "test-datasource"},
+			"ioConfig":   map[string]interface{}{"topic": "test-topic", "taskCount": float64(4)},
+		},
+	}, "2024-01-01T00:00:00.000Z")
+	assert.Equal(t, "", d.Get("spec_version"))
+	assert.Equal(t, 1, d.Get("task_count"))
+
+	// So does a version that is no longer in the history
+	d = read(pinnedSpec, "2023-01-01T00:00:00.000Z")
+	assert.Equal(t, "", d.Get("spec_version"))
+}
+
+func TestResourceKafkaSupervisorDelete(t *testing.T) {
+	tests := []struct {
+		name             string
+		deletionPolicy   string
+		killData         bool
+		expectSupervisor bool
+		expectState      string
+		expectUnused     bool
+		expectKilled     bool
+	}{
+		{
+			name:             "terminate",
+			deletionPolicy:   "terminate",
+			expectSupervisor: false,
+		},
+		{
+			name:             "suspend only",
+			deletionPolicy:   "suspend_only",
+			expectSupervisor: true,
+			expectState:      "SUSPENDED",
+		},
+		{
+			name:             "terminate and drop data",
+			deletionPolicy:   "terminate_and_drop_data",
+			expectSupervisor: false,
+			expectUnused:     true,
+		},
+		{
+			name:             "terminate, drop and kill data",
+			deletionPolicy:   "terminate_and_drop_data",
+			killData:         true,
+			expectSupervisor: false,
+			expectUnused:     true,
+			expectKilled:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := NewMockDruidServer()
+			defer mockServer.Close()
+
+			mockServer.AddSupervisor("test-supervisor", "RUNNING")
+
+			client := &Client{
+				HTTPClient: http.DefaultClient,
+				Endpoint:   mockServer.URL(),
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+				"datasource":          "test-datasource",
+				"deletion_policy":     tt.deletionPolicy,
+				"kill_data_on_delete": tt.killData,
+			})
+			d.SetId("test-supervisor")
+
+			diags := resourceKafkaSupervisorDelete(context.Background(), d, client)
+			require.False(t, diags.HasError())
+			assert.Empty(t, d.Id())
+
+			supervisor := mockServer.GetSupervisor("test-supervisor")
+			if tt.expectSupervisor {
+				require.NotNil(t, supervisor)
+				assert.Equal(t, tt.expectState, supervisor.State)
+			} else {
+				assert.Nil(t, supervisor)
+			}
+			assert.Equal(t, tt.expectUnused, mockServer.IsDatasourceUnused("test-datasource"))
+			assert.Equal(t, tt.expectKilled, mockServer.IsDatasourceKilled("test-datasource"))
+		})
+	}
+}
+
+func TestResourceKafkaSupervisorDeleteWaitsForTasks(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.AddSupervisor("test-supervisor", "RUNNING")
+	mockServer.AddSupervisorTask("test-supervisor", "index_kafka_test_1", "RUNNING", "RUNNING", "SUCCESS")
+	mockServer.AddSupervisorTask("test-supervisor", "index_kafka_test_2", "RUNNING", "FAILED")
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource":      "test-datasource",
+		"deletion_policy": "terminate_and_drop_data",
+	})
+	d.SetId("test-supervisor")
+
+	// Segments are only dropped after the tasks stopped publishing
+	diags := resourceKafkaSupervisorDelete(context.Background(), d, client)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "SUCCESS", mockServer.TaskStatus("index_kafka_test_1"))
+	assert.Equal(t, "FAILED", mockServer.TaskStatus("index_kafka_test_2"))
+	assert.True(t, mockServer.IsDatasourceUnused("test-datasource"))
+}
+
+func TestResourceKafkaSupervisorDeleteRetriesDrop(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.AddSupervisor("test-supervisor", "RUNNING")
+	mockServer.SetSupervisorSpec("test-supervisor", map[string]interface{}{"type": "kafka"})
+	mockServer.FailDatasourceDrops(1)
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource":      "test-datasource",
+		"deletion_policy": "terminate_and_drop_data",
+	})
+	d.SetId("test-supervisor")
+
+	// The supervisor is terminated but its data could not be dropped
+	diags := resourceKafkaSupervisorDelete(context.Background(), d, client)
+	require.True(t, diags.HasError())
+	assert.Nil(t, mockServer.GetSupervisor("test-supervisor"))
+	assert.False(t, mockServer.IsDatasourceUnused("test-datasource"))
+	assert.Equal(t, "test-supervisor", d.Id())
+	assert.Equal(t, terminatedSupervisorState, d.Get("state"))
+
+	// Refreshing keeps the resource, and retrying the destroy drops the data
+	require.False(t, resourceKafkaSupervisorRead(context.Background(), d, client).HasError())
+	assert.Equal(t, "test-supervisor", d.Id())
+
+	diags = resourceKafkaSupervisorDelete(context.Background(), d, client)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+	assert.True(t, mockServer.IsDatasourceUnused("test-datasource"))
+}
+
+func TestResourceKafkaSupervisorDeleteSharedDatasource(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	datasourceSpec := func(datasource string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "kafka",
+			"spec": map[string]interface{}{
+				"dataSchema": map[string]interface{}{"dataSource": datasource},
+			},
+		}
+	}
+	mockServer.AddSupervisor("clicks", "RUNNING")
+	mockServer.SetSupervisorSpec("clicks", datasourceSpec("events"))
+	mockServer.AddSupervisor("views", "RUNNING")
+	mockServer.SetSupervisorSpec("views", datasourceSpec("events"))
+	mockServer.AddSupervisor("metrics", "RUNNING")
+	mockServer.SetSupervisorSpec("metrics", datasourceSpec("metrics"))
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource":      "events",
+		"supervisor_id":   "clicks",
+		"deletion_policy": "terminate_and_drop_data",
+	})
+	d.SetId("clicks")
+
+	// Another supervisor still feeds the datasource, so nothing is touched
+	diags := resourceKafkaSupervisorDelete(context.Background(), d, client)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "also fed by supervisors views")
+	assert.Equal(t, "clicks", d.Id())
+	assert.NotNil(t, mockServer.GetSupervisor("clicks"))
+	assert.False(t, mockServer.IsDatasourceUnused("events"))
+
+	// Once it is the last one, the data is dropped
+	require.NoError(t, client.DeleteSupervisor(context.Background(), "views"))
+	diags = resourceKafkaSupervisorDelete(context.Background(), d, client)
+	require.False(t, diags.HasError())
+	assert.Nil(t, mockServer.GetSupervisor("clicks"))
+	assert.True(t, mockServer.IsDatasourceUnused("events"))
+	assert.False(t, mockServer.IsDatasourceUnused("metrics"))
+}
+
+func TestResourceKafkaSupervisorCreateExisting(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.AddSupervisor("test-datasource", "RUNNING")
+	mockServer.SetSupervisorSpec("test-datasource", map[string]interface{}{"type": "kafka"})
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+	})
+
+	diags := resourceKafkaSupervisorCreate(context.Background(), d, client)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "already exists")
+	assert.Equal(t, 0, mockServer.SubmissionCount("test-datasource"))
+}
+
+func TestResourceKafkaSupervisorCreateIgnoredSupervisorID(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.IgnoreSupervisorIDs()
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource":    "test-datasource",
+		"supervisor_id": "test-datasource-eu",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{"column": "__time"},
+		},
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{"type": "json"},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+	})
+
+	// The supervisor Druid created under its own ID is not left running
+	diags := resourceKafkaSupervisorCreate(context.Background(), d, client)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "instead of the requested supervisor_id test-datasource-eu")
+	assert.Empty(t, d.Id())
+	assert.Equal(t, 1, mockServer.SubmissionCount("test-datasource-supervisor"))
+	assert.Nil(t, mockServer.GetSupervisor("test-datasource-supervisor"))
+}
+
+func TestResourceKafkaSupervisorRecreateAfterSuspendOnly(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	config := map[string]interface{}{
+		"datasource":    "test-datasource",
+		"supervisor_id": "test-supervisor",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{"column": "__time"},
+		},
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{"type": "json"},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+		"deletion_policy": "suspend_only",
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, config)
+	require.False(t, resourceKafkaSupervisorCreate(context.Background(), d, client).HasError())
+	require.False(t, resourceKafkaSupervisorDelete(context.Background(), d, client).HasError())
+	require.Equal(t, "SUSPENDED", mockServer.GetSupervisor("test-supervisor").State)
+
+	// The suspended supervisor left behind is taken over and resumed
+	d = schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, config)
+	diags := resourceKafkaSupervisorCreate(context.Background(), d, client)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, "test-supervisor", d.Id())
+	assert.Equal(t, 2, mockServer.SubmissionCount("test-supervisor"))
+	assert.Equal(t, "RUNNING", mockServer.GetSupervisor("test-supervisor").State)
+
+	// A suspended supervisor of another datasource is not
+	config["datasource"] = "other-datasource"
+	require.NoError(t, client.SuspendSupervisor(context.Background(), "test-supervisor"))
+	d = schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, config)
+	diags = resourceKafkaSupervisorCreate(context.Background(), d, client)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "already exists")
+	assert.Equal(t, 2, mockServer.SubmissionCount("test-supervisor"))
+}
+
+func TestResourceKafkaSupervisorUpdateProviderOnly(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	config := map[string]interface{}{
+		"datasource": "test-datasource",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{"column": "__time"},
+		},
+		"topic": "test-topic",
+		"input_format": []interface{}{
+			map[string]interface{}{"type": "json"},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, config)
+	require.False(t, resourceKafkaSupervisorCreate(context.Background(), d, client).HasError())
+	require.Equal(t, 1, mockServer.SubmissionCount(d.Id()))
+
+	config["deletion_policy"] = "suspend_only"
+	d = updatedResourceData(t, d.State(), config)
+	require.True(t, d.HasChange("deletion_policy"))
+
+	// Nothing reaches Druid, so there is no change to wait for
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	diags := resourceKafkaSupervisorUpdate(ctx, d, client)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.NoError(t, ctx.Err())
+	assert.Equal(t, 1, mockServer.SubmissionCount(d.Id()))
+	assert.Equal(t, "suspend_only", d.Get("deletion_policy"))
+}
+
+// updatedResourceData returns resource data planned from state to config,
+// as Terraform passes it to Update.
+func updatedResourceData(t *testing.T, state *terraform.InstanceState, config map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	r := resourceKafkaSupervisor()
+	diff, err := r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	require.NoError(t, err)
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	require.NoError(t, err)
+	return d
+}
+
+func TestAdoptSupervisorID(t *testing.T) {
+	mockServer := NewMockDruidServer()
+	defer mockServer.Close()
+
+	mockServer.AddSupervisor("old-supervisor", "RUNNING")
+	mockServer.AddSupervisor("new-supervisor", "RUNNING")
+
+	client := &Client{
+		HTTPClient: http.DefaultClient,
+		Endpoint:   mockServer.URL(),
+	}
+
+	d := resourceKafkaSupervisor().TestResourceData()
+	d.SetId("old-supervisor")
+
+	// The same ID is left alone
+	require.NoError(t, adoptSupervisorID(context.Background(), client, d, "old-supervisor"))
+	assert.Equal(t, "old-supervisor", d.Id())
+	assert.NotNil(t, mockServer.GetSupervisor("old-supervisor"))
+
+	// A different ID replaces the tracked supervisor
+	require.NoError(t, adoptSupervisorID(context.Background(), client, d, "new-supervisor"))
+	assert.Equal(t, "new-supervisor", d.Id())
+	assert.Equal(t, "new-supervisor", d.Get("supervisor_id"))
+	assert.Nil(t, mockServer.GetSupervisor("old-supervisor"))
+	assert.NotNil(t, mockServer.GetSupervisor("new-supervisor"))
+}
+
+func TestSuppressEquivalentDurations(t *testing.T) {
+	assert.True(t, suppressEquivalentDurations("task_duration", "PT3600S", "PT1H", nil))
+	assert.True(t, suppressEquivalentDurations("task_duration", "PT1H30M", "PT90M", nil))
+	assert.True(t, suppressEquivalentDurations("task_duration", "P1D", "PT24H", nil))
+	assert.False(t, suppressEquivalentDurations("task_duration", "PT1H", "PT2H", nil))
+	assert.False(t, suppressEquivalentDurations("task_duration", "P1M", "PT720H", nil))
+}
+
+func TestSuppressEquivalentIntervals(t *testing.T) {
+	assert.True(t, suppressEquivalentIntervals("intervals", "2020-01-01T00:00:00.000Z/2020-02-01T00:00:00.000Z", "2020-01-01/2020-02-01", nil))
+	assert.True(t, suppressEquivalentIntervals("intervals", "2020-01-01T00:00:00.000Z/2020-02-01T00:00:00.000Z", "2020-01-01/P1M", nil))
+	assert.True(t, suppressEquivalentIntervals("intervals", "2020-01-01T00:00:00.000Z/2020-01-02T00:00:00.000Z", "2020-01-01T02:00:00+02:00/P1D", nil))
+	assert.False(t, suppressEquivalentIntervals("intervals", "2020-01-01/2020-02-01", "2020-01-01/2020-03-01", nil))
+}
+
+func TestValidateISO8601(t *testing.T) {
+	for _, v := range []string{"PT1H", "P1D", "P1Y2M3W4DT5H6M7.5S", "PT0.5S"} {
+		_, errs := validateISO8601Period(v, "period")
+		assert.Empty(t, errs, v)
+	}
+	for _, v := range []string{"", "P", "PT", "1H", "P1H", "PT1D"} {
+		_, errs := validateISO8601Period(v, "period")
+		assert.Len(t, errs, 1, v)
+	}
+
+	for _, v := range []string{"2020-01-01", "2020-01-01T10:00", "2020-01-01T10:00:00Z", "2020-01-01T10:00:00.123-08:00"} {
+		_, errs := validateISO8601DateTime(v, "origin")
+		assert.Empty(t, errs, v)
+	}
+	for _, v := range []string{"", "yesterday", "2020-13-01", "01/01/2020"} {
+		_, errs := validateISO8601DateTime(v, "origin")
+		assert.Len(t, errs, 1, v)
+	}
+
+	for _, v := range []string{"2020-01-01/2020-02-01", "2020-01-01/P1M", "P1D/2020-01-02T00:00:00Z"} {
+		_, errs := validateISO8601Interval(v, "intervals")
+		assert.Empty(t, errs, v)
+	}
+	for _, v := range []string{"2020-01-01", "2020-02-01/2020-01-01", "2020-01-01/2020-02-01/2020-03-01", "P1D/P2D"} {
+		_, errs := validateISO8601Interval(v, "intervals")
+		assert.Len(t, errs, 1, v)
+	}
+}
+
+func TestResourceKafkaSupervisorSchema(t *testing.T) {
+	resource := resourceKafkaSupervisor()
+	
+	// Test required fields
+	assert.True(t, resource.Schema["datasource"].Required)
+	assert.True(t, resource.Schema["timestamp_spec"].Required)
+	assert.True(t, resource.Schema["input_format"].Required)
+	assert.True(t, resource.Schema["consumer_properties"].Required)
+	
+	// Test fields that replace the supervisor
+	assert.True(t, resource.Schema["datasource"].ForceNew)
+	assert.True(t, resource.Schema["supervisor_id"].ForceNew)
+	assert.True(t, resource.Schema["supervisor_id"].Optional)
 	
 	// Test computed fields
 	assert.True(t, resource.Schema["supervisor_id"].Computed)
 	assert.True(t, resource.Schema["state"].Computed)
+	assert.True(t, resource.Schema["detailed_state"].Computed)
+	assert.True(t, resource.Schema["healthy"].Computed)
+	assert.True(t, resource.Schema["aggregate_lag"].Computed)
+	assert.True(t, resource.Schema["recent_errors"].Computed)
 	
 	// Test optional fields with defaults
 	assert.Equal(t, 1, resource.Schema["task_count"].Default)
@@ -6,12 +6,21 @@ import (
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"time"
 )
 
 // MockDruidServer provides a mock Druid server for testing
 type MockDruidServer struct {
 	server      *httptest.Server
 	supervisors map[string]*SupervisorStatus
+	specs       map[string]map[string]interface{}
+	submissions map[string]int
+	history     map[string][]SupervisorHistoryEntry
+	unused      map[string]bool
+	killed      map[string]bool
+	tasks       map[string][]string
+	dropErrors  int
+	ignoreIDs   bool
 	mutex       sync.RWMutex
 }
 
@@ -19,12 +28,31 @@ type MockDruidServer struct {
 func NewMockDruidServer() *MockDruidServer {
 	mock := &MockDruidServer{
 		supervisors: make(map[string]*SupervisorStatus),
+		specs:       make(map[string]map[string]interface{}),
+		submissions: make(map[string]int),
+		history:     make(map[string][]SupervisorHistoryEntry),
+		unused:      make(map[string]bool),
+		killed:      make(map[string]bool),
+		tasks:       make(map[string][]string),
 	}
 	
 	mux := http.NewServeMux()
 	
-	// Create or update supervisor
+	// List, create or update supervisors
 	mux.HandleFunc("/druid/indexer/v1/supervisor", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mock.mutex.RLock()
+			supervisors := []SupervisorSummary{}
+			for id := range mock.supervisors {
+				supervisors = append(supervisors, SupervisorSummary{ID: id, Spec: mock.specs[id]})
+			}
+			mock.mutex.RUnlock()
+			
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(supervisors)
+			return
+		}
+		
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
@@ -36,7 +64,7 @@ func NewMockDruidServer() *MockDruidServer {
 			return
 		}
 		
-		// Extract datasource from spec for ID generation
+		// Use the requested ID, or extract datasource from spec for ID generation
 		supervisorID := "test-supervisor-id"
 		if specData, ok := spec["spec"].(map[string]interface{}); ok {
 			if dataSchema, ok := specData["dataSchema"].(map[string]interface{}); ok {
@@ -45,12 +73,31 @@ func NewMockDruidServer() *MockDruidServer {
 				}
 			}
 		}
+		mock.mutex.RLock()
+		ignoreIDs := mock.ignoreIDs
+		mock.mutex.RUnlock()
+		if id, ok := spec["id"].(string); ok && id != "" && !ignoreIDs {
+			supervisorID = id
+		}
+		
+		state := "RUNNING"
+		if suspended, ok := spec["suspended"].(bool); ok && suspended {
+			state = "SUSPENDED"
+		}
 		
 		mock.mutex.Lock()
 		mock.supervisors[supervisorID] = &SupervisorStatus{
-			ID:    supervisorID,
-			State: "RUNNING",
+			ID:            supervisorID,
+			State:         state,
+			DetailedState: state,
+			Healthy:       true,
 		}
+		mock.specs[supervisorID] = spec
+		mock.submissions[supervisorID]++
+		mock.history[supervisorID] = append([]SupervisorHistoryEntry{{
+			Spec:    spec,
+			Version: time.Now().UTC().Format(time.RFC3339Nano),
+		}}, mock.history[supervisorID]...)
 		mock.mutex.Unlock()
 		
 		response := SupervisorResponse{ID: supervisorID}
@@ -58,13 +105,8 @@ func NewMockDruidServer() *MockDruidServer {
 		json.NewEncoder(w).Encode(response)
 	})
 	
-	// Get supervisor status
+	// Get supervisor spec or status
 	mux.HandleFunc("/druid/indexer/v1/supervisor/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		
 		// Extract supervisor ID from URL
 		path := strings.TrimPrefix(r.URL.Path, "/druid/indexer/v1/supervisor/")
 		supervisorID := strings.TrimSuffix(path, "/status")
@@ -86,6 +128,7 @@ func NewMockDruidServer() *MockDruidServer {
 					} else {
 						supervisor.State = "RUNNING"
 					}
+					supervisor.DetailedState = supervisor.State
 				}
 				mock.mutex.Unlock()
 				
@@ -94,6 +137,27 @@ func NewMockDruidServer() *MockDruidServer {
 				return
 			}
 			
+			if strings.HasSuffix(path, "/history") {
+				if r.Method != http.MethodGet {
+					http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+					return
+				}
+				supervisorID = strings.TrimSuffix(supervisorID, "/history")
+				
+				mock.mutex.RLock()
+				history, exists := mock.history[supervisorID]
+				mock.mutex.RUnlock()
+				
+				if !exists {
+					http.Error(w, "Supervisor not found", http.StatusNotFound)
+					return
+				}
+				
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(history)
+				return
+			}
+			
 			if strings.HasSuffix(path, "/terminate") {
 				if r.Method != http.MethodPost {
 					http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -103,6 +167,7 @@ func NewMockDruidServer() *MockDruidServer {
 				
 				mock.mutex.Lock()
 				delete(mock.supervisors, supervisorID)
+				delete(mock.specs, supervisorID)
 				mock.mutex.Unlock()
 				
 				w.WriteHeader(http.StatusOK)
@@ -111,8 +176,14 @@ func NewMockDruidServer() *MockDruidServer {
 			}
 		}
 		
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		
 		mock.mutex.RLock()
 		supervisor, exists := mock.supervisors[supervisorID]
+		spec := mock.specs[supervisorID]
 		mock.mutex.RUnlock()
 		
 		if !exists {
@@ -121,9 +192,70 @@ func NewMockDruidServer() *MockDruidServer {
 		}
 		
 		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(path, "/status") {
+			// Get supervisor spec
+			json.NewEncoder(w).Encode(spec)
+			return
+		}
 		json.NewEncoder(w).Encode(supervisor)
 	})
 	
+	// Get task status, advancing through the statuses set for the task
+	mux.HandleFunc("/druid/indexer/v1/task/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		
+		taskID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/druid/indexer/v1/task/"), "/status")
+		
+		mock.mutex.Lock()
+		statuses, exists := mock.tasks[taskID]
+		if len(statuses) > 1 {
+			mock.tasks[taskID] = statuses[1:]
+		}
+		mock.mutex.Unlock()
+		
+		if !exists {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"task":   taskID,
+			"status": map[string]interface{}{"id": taskID, "statusCode": statuses[0]},
+		})
+	})
+	
+	// Mark datasource segments unused or kill them
+	mux.HandleFunc("/druid/coordinator/v1/datasources/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		
+		path := strings.TrimPrefix(r.URL.Path, "/druid/coordinator/v1/datasources/")
+		datasource, interval, isKill := strings.Cut(path, "/intervals/")
+		
+		mock.mutex.Lock()
+		if mock.dropErrors > 0 {
+			mock.dropErrors--
+			mock.mutex.Unlock()
+			http.Error(w, "Coordinator unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if isKill && interval != "" {
+			mock.killed[datasource] = true
+		} else {
+			mock.unused[datasource] = true
+		}
+		mock.mutex.Unlock()
+		
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"numChangedSegments": 0}`))
+	})
+	
 	mock.server = httptest.NewServer(mux)
 	return mock
 }
@@ -155,9 +287,94 @@ func (m *MockDruidServer) AddSupervisor(id, state string) {
 	}
 }
 
+// AddSupervisorTask adds an active task to a supervisor. Each status poll of
+// the task returns the next of statuses, and the last one is repeated.
+func (m *MockDruidServer) AddSupervisorTask(id, taskID string, statuses ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if supervisor, exists := m.supervisors[id]; exists {
+		supervisor.ActiveTasks = append(supervisor.ActiveTasks, SupervisorTask{ID: taskID})
+	}
+	m.tasks[taskID] = statuses
+}
+
+// TaskStatus returns the status the task reports next
+func (m *MockDruidServer) TaskStatus(taskID string) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if statuses := m.tasks[taskID]; len(statuses) > 0 {
+		return statuses[0]
+	}
+	return ""
+}
+
+// GetSupervisorSpec returns the spec last submitted for a supervisor
+func (m *MockDruidServer) GetSupervisorSpec(id string) map[string]interface{} {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.specs[id]
+}
+
+// SetSupervisorSpec replaces the spec of a supervisor, simulating an
+// out-of-band change made outside of Terraform
+func (m *MockDruidServer) SetSupervisorSpec(id string, spec map[string]interface{}) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.specs[id] = spec
+}
+
+// SubmissionCount returns how many times a spec was posted for a supervisor
+func (m *MockDruidServer) SubmissionCount(id string) int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.submissions[id]
+}
+
+// AddSupervisorHistory records a spec version for a supervisor
+func (m *MockDruidServer) AddSupervisorHistory(id string, entry SupervisorHistoryEntry) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.history[id] = append([]SupervisorHistoryEntry{entry}, m.history[id]...)
+}
+
+// IsDatasourceUnused reports whether the datasource's segments were marked unused
+func (m *MockDruidServer) IsDatasourceUnused(datasource string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.unused[datasource]
+}
+
+// IgnoreSupervisorIDs makes the server name supervisors after their datasource
+// like Druid versions without explicit supervisor IDs
+func (m *MockDruidServer) IgnoreSupervisorIDs() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.ignoreIDs = true
+}
+
+// FailDatasourceDrops makes the next count requests to mark segments unused
+// or kill them fail
+func (m *MockDruidServer) FailDatasourceDrops(count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.dropErrors = count
+}
+
+// IsDatasourceKilled reports whether a kill task was issued for the datasource
+func (m *MockDruidServer) IsDatasourceKilled(datasource string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.killed[datasource]
+}
+
 // ClearSupervisors removes all supervisors
 func (m *MockDruidServer) ClearSupervisors() {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	m.supervisors = make(map[string]*SupervisorStatus)
+	m.specs = make(map[string]map[string]interface{})
+	m.submissions = make(map[string]int)
+	m.history = make(map[string][]SupervisorHistoryEntry)
+	m.unused = make(map[string]bool)
+	m.killed = make(map[string]bool)
 }
\ No newline at end of file
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+)
+
+// javaOnlyRegexConstructs match syntax that java.util.regex accepts but Go
+// cannot parse, so patterns using them are left for Druid to check.
+var javaOnlyRegexConstructs = []*regexp.Regexp{
+	regexp.MustCompile(`\(\?<?[=!]`),                              // lookahead and lookbehind
+	regexp.MustCompile(`\(\?>`),                                   // atomic groups
+	regexp.MustCompile(`\\[1-9]|\\k<`),                            // backreferences
+	regexp.MustCompile(`(?:^|[^\\])(?:[*+?]|\{\d+(?:,\d*)?\})\+`), // possessive quantifiers
+	regexp.MustCompile(`(?:^|[^\\])\\[hHRXGZeu]`),                 // escapes Go lacks
+	// POSIX, java.lang.Character and Is/In Unicode property classes
+	regexp.MustCompile(`(?:^|[^\\])\\[pP]\{(?:Lower|Upper|ASCII|Alpha|Digit|Alnum|Punct|Graph|Print|Blank|Cntrl|XDigit|Space|java[A-Za-z]+|I[sn][A-Za-z_]+)\}`),
+}
+
+// goOnlyRegexConstructs match syntax that Go accepts but Java rejects or
+// reads differently, with the reason reported to the user.
+var goOnlyRegexConstructs = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`\(\?P<`), "Java does not support (?P<name>...) groups, use (?<name>...)"},
+	{regexp.MustCompile(`\[\[:\^?[a-z]+:\]`), `Java does not support POSIX classes such as [[:alpha:]], use \p{Alpha}`},
+	{regexp.MustCompile(`\(\?[a-zA-Z]*U`), "the U flag enables Unicode character classes in Java rather than ungreedy matching"},
+}
+
+// validateTopicPattern checks that a topic pattern compiles as a Java
+// regular expression, which is how Druid matches topic names, and warns
+// about patterns that match every topic.
+func validateTopicPattern(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if v == "" {
+		return nil, []error{fmt.Errorf("%s must not be empty", k)}
+	}
+
+	for _, construct := range goOnlyRegexConstructs {
+		if construct.pattern.MatchString(v) {
+			return nil, []error{fmt.Errorf("%s %q is not a valid Java regular expression: %s", k, v, construct.reason)}
+		}
+	}
+	for _, construct := range javaOnlyRegexConstructs {
+		if construct.MatchString(v) {
+			return []string{fmt.Sprintf("%s %q uses Java regular expression features that can only be checked by Druid", k, v)}, nil
+		}
+	}
+
+	re, err := syntax.Parse(v, syntax.Perl)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s %q is not a valid regular expression: %w", k, v, err)}
+	}
+	if matchesEveryTopic(re.Simplify()) {
+		return []string{fmt.Sprintf("%s %q matches every topic, including internal topics such as __consumer_offsets", k, v)}, nil
+	}
+
+	return nil, nil
+}
+
+// matchesEveryTopic reports whether re matches any non-empty topic name.
+func matchesEveryTopic(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpCapture:
+		return matchesEveryTopic(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		return re.Sub[0].Op == syntax.OpAnyChar || re.Sub[0].Op == syntax.OpAnyCharNotNL
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if matchesEveryTopic(sub) {
+				return true
+			}
+		}
+	case syntax.OpConcat:
+		matched := false
+		for _, sub := range re.Sub {
+			switch sub.Op {
+			case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText, syntax.OpEmptyMatch:
+				continue
+			}
+			if !matchesEveryTopic(sub) {
+				return false
+			}
+			matched = true
+		}
+		return matched
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTopicPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		wantErr  string
+		wantWarn string
+	}{
+		{name: "prefix", pattern: "events-.*"},
+		{name: "alternation", pattern: "(clicks|views)-v[0-9]+"},
+		{name: "java named group", pattern: `(?<env>prod|staging)\.events`},
+		{name: "empty", pattern: "", wantErr: "must not be empty"},
+		{name: "unbalanced group", pattern: "events-(.*", wantErr: "is not a valid regular expression"},
+		{name: "bad repetition", pattern: "events-**", wantErr: "is not a valid regular expression"},
+		{name: "go named group", pattern: `(?P<env>prod)\.events`, wantErr: "(?<name>...)"},
+		{name: "posix class", pattern: "events-[[:digit:]]+", wantErr: `\p{Alpha}`},
+		{name: "ungreedy flag", pattern: "(?U)events-.*", wantErr: "U flag"},
+		{name: "lookahead", pattern: "events-(?!test).*", wantWarn: "can only be checked by Druid"},
+		{name: "possessive", pattern: "events-.*+", wantWarn: "can only be checked by Druid"},
+		{name: "possessive repetition", pattern: "events-[0-9]{2,4}+", wantWarn: "can only be checked by Druid"},
+		{name: "possessive group", pattern: "(events|logs)++-[0-9]+", wantWarn: "can only be checked by Druid"},
+		{name: "lookahead with class", pattern: `(?=\w)events-[a-z]+`, wantWarn: "can only be checked by Druid"},
+		{name: "java posix class", pattern: `events-\p{Alpha}+`, wantWarn: "can only be checked by Druid"},
+		{name: "java posix negated class", pattern: `events-\P{Lower}\p{XDigit}+`, wantWarn: "can only be checked by Druid"},
+		{name: "java character class", pattern: `\p{javaLowerCase}+-events`, wantWarn: "can only be checked by Druid"},
+		{name: "java unicode block", pattern: `\p{InGreek}+`, wantWarn: "can only be checked by Druid"},
+		{name: "unicode escape", pattern: `events\u002D.*`, wantWarn: "can only be checked by Druid"},
+		{name: "unicode script", pattern: `\p{Greek}+-events`},
+		{name: "escaped posix class", pattern: `events-\\p{Alpha}`},
+		{name: "match all", pattern: ".*", wantWarn: "matches every topic"},
+		{name: "anchored match all", pattern: "^(.+)$", wantWarn: "matches every topic"},
+		{name: "match all alternative", pattern: "events|.*", wantWarn: "matches every topic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, errs := validateTopicPattern(tt.pattern, "topic_pattern")
+			if tt.wantErr != "" {
+				require.Len(t, errs, 1)
+				assert.Contains(t, errs[0].Error(), tt.wantErr)
+				return
+			}
+			assert.Empty(t, errs)
+			if tt.wantWarn != "" {
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0], tt.wantWarn)
+			} else {
+				assert.Empty(t, warnings)
+			}
+		})
+	}
+}
+
+func TestMultiTopicIngestion(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{
+		"datasource": "test-datasource",
+		"timestamp_spec": []interface{}{
+			map[string]interface{}{"column": "__time"},
+		},
+		"topic_pattern": "events-(clicks|views)",
+		"input_format": []interface{}{
+			map[string]interface{}{
+				"type":              "kafka",
+				"topic_column_name": "source_topic",
+				"value_format": []interface{}{
+					map[string]interface{}{"type": "json"},
+				},
+			},
+		},
+		"consumer_properties": map[string]interface{}{
+			"bootstrap.servers": "localhost:9092",
+		},
+	})
+
+	spec, err := buildSupervisorSpec(d)
+	require.NoError(t, err)
+	ioConfig := spec["spec"].(map[string]interface{})["ioConfig"].(map[string]interface{})
+	assert.Equal(t, "events-(clicks|views)", ioConfig["topicPattern"])
+	assert.NotContains(t, ioConfig, "topic")
+	assert.Equal(t, "source_topic", ioConfig["inputFormat"].(map[string]interface{})["topicColumnName"])
+
+	// Both are read back from the spec Druid returns
+	var druidSpec map[string]interface{}
+	require.NoError(t, copyJSON(spec, &druidSpec))
+	imported := schema.TestResourceDataRaw(t, resourceKafkaSupervisor().Schema, map[string]interface{}{})
+	require.NoError(t, flattenSupervisorSpec(imported, druidSpec, true))
+	assert.Equal(t, "events-(clicks|views)", imported.Get("topic_pattern"))
+	assert.Equal(t, "", imported.Get("topic"))
+	assert.Equal(t, "source_topic", imported.Get("input_format.0.topic_column_name"))
+}